@@ -0,0 +1,193 @@
+package main
+
+import (
+	apiKeysStorage "app/internal/apikeys/storage"
+	"app/internal/auth"
+	"app/internal/config"
+	invoicesStorage "app/internal/invoices/storage"
+	"app/internal/jsondb"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// command is a cli subcommand
+type command struct {
+	// usage is the one line description of the command
+	usage string
+	// run executes the command with the storages of the database (ctx is cancelled on interrupt)
+	run func(ctx context.Context, st *storages, args []string) (err error)
+}
+
+// storages are the storages of the configured database used by the commands
+type storages struct {
+	invoice invoicesStorage.StorageInvoice
+	apiKey  apiKeysStorage.StorageAPIKey
+}
+
+// newStorages returns the storages of the database configuration on db (nil with the json driver)
+func newStorages(c *config.ConfigDb, db *sql.DB) *storages {
+	switch c.Driver {
+	case config.DriverJSON:
+		return &storages{
+			invoice: invoicesStorage.NewStorageInvoiceJSON(filepath.Join(c.Path, jsondb.FileInvoices)),
+			apiKey:  apiKeysStorage.NewStorageAPIKeyJSON(filepath.Join(c.Path, jsondb.FileAPIKeys)),
+		}
+	case config.DriverSQLite:
+		return &storages{
+			invoice: invoicesStorage.NewStorageInvoiceSQLite(db),
+			apiKey:  apiKeysStorage.NewStorageAPIKeySQLite(db),
+		}
+	}
+	return &storages{
+		invoice: invoicesStorage.NewStorageInvoiceMySQL(db),
+		apiKey:  apiKeysStorage.NewStorageAPIKeyMySQL(db),
+	}
+}
+
+// commands are the available subcommands
+var commands = map[string]command{
+	"update-totals": {
+		usage: "recompute the total of every invoice from its sales and the product prices",
+		run:   updateTotals,
+	},
+	"apikey-create": {
+		usage: "<name> <reader|cashier|admin> create an api key (it is printed once, only its hash is stored)",
+		run:   apiKeyCreate,
+	},
+	"apikey-revoke": {
+		usage: "<id> revoke an api key",
+		run:   apiKeyRevoke,
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes the subcommand in args and returns the exit code
+func run(args []string) (code int) {
+	// command
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+		usage(os.Stderr)
+		return 2
+	}
+
+	// context (cancelled on interrupt, so the running queries are aborted)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// dependencies
+	// - config
+	cfg, err := config.Load(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// - database (none with the json driver, whose storages open the files of the directory)
+	var db *sql.DB
+	if cfg.Db.Driver != config.DriverJSON {
+		db, err = cfg.Db.Open()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer db.Close()
+		if err = db.PingContext(ctx); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	// execute
+	if err = cmd.run(ctx, newStorages(&cfg.Db, db), args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// usage writes the list of commands to w
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: cli <command>")
+	fmt.Fprintln(w, "commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-15s %s\n", name, commands[name].usage)
+	}
+}
+
+// updateTotals recomputes the invoice totals
+func updateTotals(ctx context.Context, st *storages, args []string) (err error) {
+	err = st.invoice.UpdateTotals(ctx)
+	if err != nil {
+		return
+	}
+
+	fmt.Println("invoice totals updated")
+	return
+}
+
+// errArgs is returned when the arguments of a command are invalid
+var errArgs = errors.New("invalid arguments")
+
+// apiKeyCreate creates an api key and prints it
+func apiKeyCreate(ctx context.Context, st *storages, args []string) (err error) {
+	if len(args) != 2 {
+		err = fmt.Errorf("%w. usage: apikey-create <name> <reader|cashier|admin>", errArgs)
+		return
+	}
+	role, err := auth.ParseRole(args[1])
+	if err != nil {
+		return
+	}
+
+	key, k, err := auth.Issue(ctx, st.apiKey, args[0], role)
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("api key %d (%s, %s) created, store it now as it can not be shown again:\n%s\n", k.Id, k.Name, role, key)
+	return
+}
+
+// apiKeyRevoke revokes an api key
+func apiKeyRevoke(ctx context.Context, st *storages, args []string) (err error) {
+	if len(args) != 1 {
+		err = fmt.Errorf("%w. usage: apikey-revoke <id>", errArgs)
+		return
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		err = fmt.Errorf("%w. id must be an integer", errArgs)
+		return
+	}
+
+	err = st.apiKey.Revoke(ctx, id, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("api key %d revoked\n", id)
+	return
+}
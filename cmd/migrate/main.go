@@ -0,0 +1,134 @@
+package main
+
+import (
+	"app/internal/config"
+	customersStorage "app/internal/customers/storage"
+	invoicesStorage "app/internal/invoices/storage"
+	"app/internal/jsondb"
+	"app/internal/memdb"
+	productsStorage "app/internal/products/storage"
+	salesStorage "app/internal/sales/storage"
+	"app/internal/sqltx"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the migration and returns the exit code
+func run() (code int) {
+	// flags
+	dir := flag.String("dir", "docs/db/json", "directory with customers.json, products.json, invoices.json and sales.json")
+	dryRun := flag.Bool("dry-run", false, "read and check the files without writing to the database")
+	flag.Parse()
+
+	// context (cancelled on interrupt, so the import is aborted and rolled back)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// data
+	data, err := LoadData(*dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// dry run
+	if *dryRun {
+		report, err := NewMigrator(nil, nil, nil, nil).Run(ctx, data, true)
+		report.Write(os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	// dependencies
+	// - config
+	cfg, err := config.Load(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// migrate
+	var report *Report
+	if cfg.Db.Driver == config.DriverJSON {
+		report, err = migrateJSON(ctx, cfg.Db.Path, data)
+	} else {
+		report, err = migrateSQL(ctx, &cfg.Db, data)
+	}
+	if report != nil {
+		report.RolledBack = err != nil
+		report.Write(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// migrateSQL imports the data set into the sql database of the configuration, all or nothing
+// (the storages share a transaction that is rolled back on any error)
+func migrateSQL(ctx context.Context, c *config.ConfigDb, data *Data) (report *Report, err error) {
+	// database
+	db, err := c.Open()
+	if err != nil {
+		return
+	}
+	defer db.Close()
+	if err = db.PingContext(ctx); err != nil {
+		return
+	}
+
+	// migrator of the driver (its storages run in the transaction tx)
+	var migrator func(tx *sql.Tx) *Migrator
+	switch c.Driver {
+	case config.DriverSQLite:
+		stCustomer := customersStorage.NewStorageCustomerSQLite(db)
+		stProduct := productsStorage.NewStorageProductSQLite(db)
+		stInvoice := invoicesStorage.NewStorageInvoiceSQLite(db)
+		stSale := salesStorage.NewStorageSaleSQLite(db)
+		migrator = func(tx *sql.Tx) *Migrator {
+			return NewMigrator(stCustomer.InTx(tx), stProduct.InTx(tx), stInvoice.InTx(tx), stSale.InTx(tx))
+		}
+	default:
+		stCustomer := customersStorage.NewStorageCustomerMySQL(db)
+		stProduct := productsStorage.NewStorageProductMySQL(db)
+		stInvoice := invoicesStorage.NewStorageInvoiceMySQL(db)
+		stSale := salesStorage.NewStorageSaleMySQL(db)
+		migrator = func(tx *sql.Tx) *Migrator {
+			return NewMigrator(stCustomer.InTx(tx), stProduct.InTx(tx), stInvoice.InTx(tx), stSale.InTx(tx))
+		}
+	}
+
+	// migrate
+	err = sqltx.NewRunner(db).WithTx(ctx, func(tx *sql.Tx) (err error) {
+		report, err = migrator(tx).Run(ctx, data, false)
+		return
+	})
+	return
+}
+
+// migrateJSON imports the data set into the json files of the directory dir, all or nothing
+// (the files are only saved if every record was imported)
+func migrateJSON(ctx context.Context, dir string, data *Data) (report *Report, err error) {
+	db := jsondb.New(jsondb.FilesFor(jsondb.TableInvoices, filepath.Join(dir, jsondb.FileInvoices)))
+	err = db.Update(func(mdb *memdb.DB) (err error) {
+		m := NewMigrator(customersStorage.NewStorageCustomerMap(mdb), productsStorage.NewStorageProductMap(mdb), invoicesStorage.NewStorageInvoiceMap(mdb), salesStorage.NewStorageSaleMap(mdb))
+		report, err = m.Run(ctx, data, false)
+		return
+	}, jsondb.TableCustomers, jsondb.TableProducts, jsondb.TableInvoices, jsondb.TableSales)
+	return
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	customersStorage "app/internal/customers/storage"
+	invoicesStorage "app/internal/invoices/storage"
+	"app/internal/jsondb"
+	productsStorage "app/internal/products/storage"
+	salesStorage "app/internal/sales/storage"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMigrateFile is returned when a json file can not be read or decoded
+	ErrMigrateFile = errors.New("migrate file invalid")
+	// ErrMigrateRelation is returned when a record references a record that is not in the data set
+	ErrMigrateRelation = errors.New("migrate relation not found")
+	// ErrMigrateDuplicate is returned when two records of a json file have the same id
+	ErrMigrateDuplicate = errors.New("migrate id duplicated")
+)
+
+// Data is the content of the json files
+type Data struct {
+	Customers []*jsondb.CustomerJSON
+	Products  []*jsondb.ProductJSON
+	Invoices  []*jsondb.InvoiceJSON
+	Sales     []*jsondb.SaleJSON
+}
+
+// LoadData reads customers.json, products.json, invoices.json and sales.json from dir
+func LoadData(dir string) (d *Data, err error) {
+	d = new(Data)
+	files := []struct {
+		name string
+		ptr  any
+	}{
+		{name: jsondb.FileCustomers, ptr: &d.Customers},
+		{name: jsondb.FileProducts, ptr: &d.Products},
+		{name: jsondb.FileInvoices, ptr: &d.Invoices},
+		{name: jsondb.FileSales, ptr: &d.Sales},
+	}
+	for _, f := range files {
+		err = jsondb.ReadFile(filepath.Join(dir, f.name), f.ptr)
+		if err != nil {
+			d = nil
+			err = fmt.Errorf("%w. %v", ErrMigrateFile, err)
+			return
+		}
+	}
+
+	return
+}
+
+// EntityReport is the result of importing one entity
+type EntityReport struct {
+	// Name is the name of the entity (table)
+	Name string
+	// Total is the number of records in the json file
+	Total int
+	// Imported is the number of records imported (or that would be imported in dry-run mode)
+	Imported int
+	// FailedId is the id of the record that failed, 0 if none failed
+	FailedId int
+	// Duplicates are the ids found more than once in the json file (only checked in dry-run mode)
+	Duplicates []int
+	// Skipped is true when the entity was not processed because a previous one failed
+	Skipped bool
+}
+
+// Report is the result of a migration
+type Report struct {
+	DryRun bool
+	// RolledBack is true when the migration failed and the records imported before the failure were rolled back
+	RolledBack bool
+	Entities   []*EntityReport
+}
+
+// Write writes a human readable summary of the report to w
+func (r *Report) Write(w io.Writer) {
+	verb := "imported"
+	if r.DryRun {
+		verb = "checked"
+		fmt.Fprintln(w, "dry run: nothing was written to the database")
+	}
+	if r.RolledBack {
+		fmt.Fprintln(w, "rolled back: nothing was written to the database")
+	}
+	for _, e := range r.Entities {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%-10s %d/%d %s", e.Name, e.Imported, e.Total, verb)
+		switch {
+		case e.Skipped:
+			sb.WriteString(" (skipped)")
+		case e.FailedId != 0:
+			fmt.Fprintf(&sb, " (failed at id %d, %d not %s)", e.FailedId, e.Total-e.Imported, verb)
+		}
+		if len(e.Duplicates) > 0 {
+			fmt.Fprintf(&sb, " (duplicate ids %v)", e.Duplicates)
+		}
+		fmt.Fprintln(w, sb.String())
+	}
+}
+
+// NewMigrator is a constructor for the migrator
+func NewMigrator(stCustomer customersStorage.StorageCustomer, stProduct productsStorage.StorageProduct, stInvoice invoicesStorage.StorageInvoice, stSale salesStorage.StorageSale) *Migrator {
+	return &Migrator{
+		stCustomer: stCustomer,
+		stProduct:  stProduct,
+		stInvoice:  stInvoice,
+		stSale:     stSale,
+	}
+}
+
+// Migrator imports the json data set through the storages in foreign-key order
+type Migrator struct {
+	stCustomer customersStorage.StorageCustomer
+	stProduct  productsStorage.StorageProduct
+	stInvoice  invoicesStorage.StorageInvoice
+	stSale     salesStorage.StorageSale
+}
+
+// Run imports the data set keeping the original ids, stopping at the first error.
+// In dry-run mode the records are converted and their ids and relations checked, but the storages are not called
+func (m *Migrator) Run(ctx context.Context, d *Data, dryRun bool) (r *Report, err error) {
+	r = &Report{DryRun: dryRun}
+	rpCustomers := &EntityReport{Name: "customers", Total: len(d.Customers)}
+	rpProducts := &EntityReport{Name: "products", Total: len(d.Products)}
+	rpInvoices := &EntityReport{Name: "invoices", Total: len(d.Invoices)}
+	rpSales := &EntityReport{Name: "sales", Total: len(d.Sales)}
+	r.Entities = []*EntityReport{rpCustomers, rpProducts, rpInvoices, rpSales}
+	skip := func(rps ...*EntityReport) {
+		for _, rp := range rps {
+			rp.Skipped = true
+		}
+	}
+
+	// customers
+	customerIds := make(map[int]bool, len(d.Customers))
+	if dryRun {
+		rpCustomers.Duplicates = duplicateIds(d.Customers, func(c *jsondb.CustomerJSON) int { return c.Id })
+	}
+	for _, c := range d.Customers {
+		if dryRun && customerIds[c.Id] {
+			rpCustomers.FailedId = c.Id
+			skip(rpProducts, rpInvoices, rpSales)
+			err = fmt.Errorf("customer %d: %w. ids %v", c.Id, ErrMigrateDuplicate, rpCustomers.Duplicates)
+			return
+		}
+
+		if !dryRun {
+			err = m.stCustomer.Create(ctx, &customersStorage.Customer{
+				Id:        c.Id,
+				FirstName: c.FirstName,
+				LastName:  c.LastName,
+				Condition: c.Condition,
+			})
+			if err != nil {
+				rpCustomers.FailedId = c.Id
+				skip(rpProducts, rpInvoices, rpSales)
+				err = fmt.Errorf("customer %d: %w", c.Id, err)
+				return
+			}
+		}
+		customerIds[c.Id] = true
+		rpCustomers.Imported++
+	}
+
+	// products
+	productIds := make(map[int]bool, len(d.Products))
+	if dryRun {
+		rpProducts.Duplicates = duplicateIds(d.Products, func(p *jsondb.ProductJSON) int { return p.Id })
+	}
+	for _, p := range d.Products {
+		if dryRun && productIds[p.Id] {
+			rpProducts.FailedId = p.Id
+			skip(rpInvoices, rpSales)
+			err = fmt.Errorf("product %d: %w. ids %v", p.Id, ErrMigrateDuplicate, rpProducts.Duplicates)
+			return
+		}
+
+		if !dryRun {
+			err = m.stProduct.Create(ctx, &productsStorage.Product{
+				Id:          p.Id,
+				Description: p.Description,
+				Price:       p.Price,
+			})
+			if err != nil {
+				rpProducts.FailedId = p.Id
+				skip(rpInvoices, rpSales)
+				err = fmt.Errorf("product %d: %w", p.Id, err)
+				return
+			}
+		}
+		productIds[p.Id] = true
+		rpProducts.Imported++
+	}
+
+	// invoices
+	invoiceIds := make(map[int]bool, len(d.Invoices))
+	if dryRun {
+		rpInvoices.Duplicates = duplicateIds(d.Invoices, func(i *jsondb.InvoiceJSON) int { return i.Id })
+	}
+	for _, i := range d.Invoices {
+		if dryRun && invoiceIds[i.Id] {
+			rpInvoices.FailedId = i.Id
+			skip(rpSales)
+			err = fmt.Errorf("invoice %d: %w. ids %v", i.Id, ErrMigrateDuplicate, rpInvoices.Duplicates)
+			return
+		}
+
+		// - deserialization
+		var dt time.Time
+		dt, err = time.Parse(jsondb.DatetimeLayout, i.Datetime)
+		if err != nil {
+			rpInvoices.FailedId = i.Id
+			skip(rpSales)
+			err = fmt.Errorf("invoice %d: %w. %v", i.Id, ErrMigrateFile, err)
+			return
+		}
+		if dryRun && !customerIds[i.CustomerId] {
+			rpInvoices.FailedId = i.Id
+			skip(rpSales)
+			err = fmt.Errorf("invoice %d: %w. customer %d", i.Id, ErrMigrateRelation, i.CustomerId)
+			return
+		}
+
+		if !dryRun {
+			err = m.stInvoice.Create(ctx, &invoicesStorage.Invoice{
+				Id:         i.Id,
+				Datetime:   dt,
+				Total:      i.Total,
+				CustomerId: i.CustomerId,
+			})
+			if err != nil {
+				rpInvoices.FailedId = i.Id
+				skip(rpSales)
+				err = fmt.Errorf("invoice %d: %w", i.Id, err)
+				return
+			}
+		}
+		invoiceIds[i.Id] = true
+		rpInvoices.Imported++
+	}
+
+	// sales
+	saleIds := make(map[int]bool, len(d.Sales))
+	if dryRun {
+		rpSales.Duplicates = duplicateIds(d.Sales, func(s *jsondb.SaleJSON) int { return s.Id })
+	}
+	for _, s := range d.Sales {
+		if dryRun && saleIds[s.Id] {
+			rpSales.FailedId = s.Id
+			err = fmt.Errorf("sale %d: %w. ids %v", s.Id, ErrMigrateDuplicate, rpSales.Duplicates)
+			return
+		}
+		if dryRun && (!productIds[s.ProductId] || !invoiceIds[s.InvoiceId]) {
+			rpSales.FailedId = s.Id
+			err = fmt.Errorf("sale %d: %w. product %d, invoice %d", s.Id, ErrMigrateRelation, s.ProductId, s.InvoiceId)
+			return
+		}
+
+		if !dryRun {
+			err = m.stSale.Create(ctx, &salesStorage.Sale{
+				Id:        s.Id,
+				Quantity:  s.Quantity,
+				ProductId: s.ProductId,
+				InvoiceId: s.InvoiceId,
+			})
+			if err != nil {
+				rpSales.FailedId = s.Id
+				err = fmt.Errorf("sale %d: %w", s.Id, err)
+				return
+			}
+		}
+		saleIds[s.Id] = true
+		rpSales.Imported++
+	}
+
+	return
+}
+
+// duplicateIds returns the ids found more than once in records, in the order of their first repetition
+func duplicateIds[T any](records []T, id func(T) int) (ids []int) {
+	seen := make(map[int]int, len(records))
+	for _, r := range records {
+		seen[id(r)]++
+		if seen[id(r)] == 2 {
+			ids = append(ids, id(r))
+		}
+	}
+	return
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	customersStorage "app/internal/customers/storage"
+	invoicesStorage "app/internal/invoices/storage"
+	"app/internal/jsondb"
+	productsStorage "app/internal/products/storage"
+	salesStorage "app/internal/sales/storage"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// storage stubs that only implement Create (the rest of the interface panics if called)
+type stubCustomer struct {
+	customersStorage.StorageCustomer
+	created []*customersStorage.Customer
+}
+
+func (s *stubCustomer) Create(ctx context.Context, c *customersStorage.Customer) (err error) {
+	s.created = append(s.created, c)
+	return
+}
+
+type stubProduct struct {
+	productsStorage.StorageProduct
+	created []*productsStorage.Product
+}
+
+func (s *stubProduct) Create(ctx context.Context, p *productsStorage.Product) (err error) {
+	s.created = append(s.created, p)
+	return
+}
+
+type stubInvoice struct {
+	invoicesStorage.StorageInvoice
+	created []*invoicesStorage.Invoice
+	failId  int
+}
+
+func (s *stubInvoice) Create(ctx context.Context, i *invoicesStorage.Invoice) (err error) {
+	if i.Id == s.failId {
+		err = invoicesStorage.ErrStorageInvoiceRelation
+		return
+	}
+	s.created = append(s.created, i)
+	return
+}
+
+type stubSale struct {
+	salesStorage.StorageSale
+	created []*salesStorage.Sale
+}
+
+func (s *stubSale) Create(ctx context.Context, sa *salesStorage.Sale) (err error) {
+	s.created = append(s.created, sa)
+	return
+}
+
+// Tests for LoadData function
+func TestLoadData(t *testing.T) {
+	t.Run("docs json files", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		d, err := LoadData("../../docs/db/json")
+
+		// assert
+		require.NoError(t, err)
+		require.NotEmpty(t, d.Customers)
+		require.NotEmpty(t, d.Products)
+		require.NotEmpty(t, d.Invoices)
+		require.NotEmpty(t, d.Sales)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		d, err := LoadData(t.TempDir())
+
+		// assert
+		require.Nil(t, d)
+		require.ErrorIs(t, err, ErrMigrateFile)
+	})
+}
+
+// Tests for Migrator.Run method
+func TestMigratorRun(t *testing.T) {
+	data := func() *Data {
+		return &Data{
+			Customers: []*jsondb.CustomerJSON{{Id: 3, FirstName: "Ike", LastName: "Fifield", Condition: false}},
+			Products:  []*jsondb.ProductJSON{{Id: 7, Description: "Beans", Price: 12.89}},
+			Invoices: []*jsondb.InvoiceJSON{
+				{Id: 10, Datetime: "2022-05-15 23:13:56", CustomerId: 3},
+				{Id: 11, Datetime: "2022-04-17 21:07:57", CustomerId: 3},
+			},
+			Sales: []*jsondb.SaleJSON{{Id: 1, ProductId: 7, InvoiceId: 10, Quantity: 2}},
+		}
+	}
+
+	t.Run("imports everything keeping the ids", func(t *testing.T) {
+		// arrange
+		stC, stP, stI, stS := &stubCustomer{}, &stubProduct{}, &stubInvoice{}, &stubSale{}
+		m := NewMigrator(stC, stP, stI, stS)
+
+		// act
+		r, err := m.Run(context.Background(), data(), false)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 3, stC.created[0].Id)
+		require.Equal(t, 7, stP.created[0].Id)
+		require.Equal(t, 11, stI.created[1].Id)
+		require.Equal(t, 2022, stI.created[0].Datetime.Year())
+		require.Equal(t, 1, stS.created[0].Id)
+		for _, e := range r.Entities {
+			require.Equal(t, e.Total, e.Imported, e.Name)
+		}
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		// arrange
+		stC, stP, stI, stS := &stubCustomer{}, &stubProduct{}, &stubInvoice{failId: 11}, &stubSale{}
+		m := NewMigrator(stC, stP, stI, stS)
+
+		// act
+		r, err := m.Run(context.Background(), data(), false)
+
+		// assert
+		require.ErrorIs(t, err, invoicesStorage.ErrStorageInvoiceRelation)
+		require.Equal(t, 1, r.Entities[2].Imported)
+		require.Equal(t, 11, r.Entities[2].FailedId)
+		require.True(t, r.Entities[3].Skipped)
+		require.Empty(t, stS.created)
+	})
+
+	t.Run("dry run does not call the storages", func(t *testing.T) {
+		// arrange
+		m := NewMigrator(nil, nil, nil, nil)
+
+		// act
+		r, err := m.Run(context.Background(), data(), true)
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, r.DryRun)
+		require.Equal(t, 2, r.Entities[2].Imported)
+	})
+
+	t.Run("dry run detects dangling relations", func(t *testing.T) {
+		// arrange
+		d := data()
+		d.Sales[0].InvoiceId = 99
+		m := NewMigrator(nil, nil, nil, nil)
+
+		// act
+		r, err := m.Run(context.Background(), d, true)
+
+		// assert
+		require.ErrorIs(t, err, ErrMigrateRelation)
+		require.Equal(t, 1, r.Entities[3].FailedId)
+	})
+
+	t.Run("dry run detects duplicate ids", func(t *testing.T) {
+		// arrange
+		d := data()
+		d.Invoices = append(d.Invoices, &jsondb.InvoiceJSON{Id: 10, Datetime: "2022-03-02 10:00:00", CustomerId: 3})
+		m := NewMigrator(nil, nil, nil, nil)
+
+		// act
+		r, err := m.Run(context.Background(), d, true)
+
+		// assert
+		require.ErrorIs(t, err, ErrMigrateDuplicate)
+		require.Equal(t, 10, r.Entities[2].FailedId)
+		require.Equal(t, []int{10}, r.Entities[2].Duplicates)
+		require.Equal(t, 2, r.Entities[2].Imported)
+		require.True(t, r.Entities[3].Skipped)
+	})
+}
+
+// Tests for Report.Write method
+func TestReportWrite(t *testing.T) {
+	t.Run("rolled back", func(t *testing.T) {
+		// arrange
+		r := &Report{RolledBack: true, Entities: []*EntityReport{
+			{Name: "customers", Total: 2, Imported: 2},
+			{Name: "products", Total: 3, Imported: 1, FailedId: 2},
+			{Name: "invoices", Total: 1, Skipped: true},
+		}}
+		var sb strings.Builder
+
+		// act
+		r.Write(&sb)
+
+		// assert
+		expected := "rolled back: nothing was written to the database\n" +
+			"customers  2/2 imported\n" +
+			"products   1/3 imported (failed at id 2, 2 not imported)\n" +
+			"invoices   0/1 imported (skipped)\n"
+		require.Equal(t, expected, sb.String())
+	})
+
+	t.Run("dry run with duplicates", func(t *testing.T) {
+		// arrange
+		r := &Report{DryRun: true, Entities: []*EntityReport{
+			{Name: "customers", Total: 3, Imported: 2, FailedId: 4, Duplicates: []int{4}},
+			{Name: "products", Total: 1, Skipped: true},
+		}}
+		var sb strings.Builder
+
+		// act
+		r.Write(&sb)
+
+		// assert
+		expected := "dry run: nothing was written to the database\n" +
+			"customers  2/3 checked (failed at id 4, 1 not checked) (duplicate ids [4])\n" +
+			"products   0/1 checked (skipped)\n"
+		require.Equal(t, expected, sb.String())
+	})
+}
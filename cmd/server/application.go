@@ -0,0 +1,274 @@
+package main
+
+import (
+	"app/cmd/server/handlers"
+	apiKeysStorage "app/internal/apikeys/storage"
+	"app/internal/auth"
+	"app/internal/checkout"
+	"app/internal/config"
+	customersStorage "app/internal/customers/storage"
+	"app/internal/health"
+	invoicesStorage "app/internal/invoices/storage"
+	"app/internal/jsondb"
+	productsStorage "app/internal/products/storage"
+	salesStorage "app/internal/sales/storage"
+	"app/pkg/web/middleware"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+// ConfigApplication is the configuration of the application
+type ConfigApplication struct {
+	// Db is the database configuration (the driver of the storages and its connection)
+	Db *config.ConfigDb
+	// Addr is the address where the server listens
+	Addr string
+	// ReadTimeout is the maximum duration for reading the entire request
+	ReadTimeout time.Duration
+	// WriteTimeout is the maximum duration before timing out writes of the response
+	WriteTimeout time.Duration
+	// IdleTimeout is the maximum amount of time to wait for the next request on keep-alive connections
+	IdleTimeout time.Duration
+	// DbTimeout is the maximum duration of the database operations of a request (0 is no limit)
+	DbTimeout time.Duration
+	// ShutdownTimeout is the maximum duration to drain the in-flight requests on shutdown
+	ShutdownTimeout time.Duration
+	// Logger is the logger of the requests, the server errors and the address it listens on (slog.Default() if nil)
+	Logger *slog.Logger
+}
+
+// NewApplication is a constructor for the application
+func NewApplication(cfg *ConfigApplication) *Application {
+	// default values
+	defaultCfg := &ConfigApplication{
+		Db:              nil,
+		Addr:            ":8080",
+		ShutdownTimeout: 15 * time.Second,
+		Logger:          slog.Default(),
+	}
+	if cfg != nil {
+		if cfg.Db != nil {
+			defaultCfg.Db = cfg.Db
+		}
+		if cfg.Addr != "" {
+			defaultCfg.Addr = cfg.Addr
+		}
+		defaultCfg.ReadTimeout = cfg.ReadTimeout
+		defaultCfg.WriteTimeout = cfg.WriteTimeout
+		defaultCfg.IdleTimeout = cfg.IdleTimeout
+		defaultCfg.DbTimeout = cfg.DbTimeout
+		if cfg.ShutdownTimeout > 0 {
+			defaultCfg.ShutdownTimeout = cfg.ShutdownTimeout
+		}
+		if cfg.Logger != nil {
+			defaultCfg.Logger = cfg.Logger
+		}
+	}
+
+	return &Application{
+		cfgDb:           defaultCfg.Db,
+		dbTimeout:       defaultCfg.DbTimeout,
+		shutdownTimeout: defaultCfg.ShutdownTimeout,
+		logger:          defaultCfg.Logger,
+		server: &http.Server{
+			Addr:         defaultCfg.Addr,
+			ReadTimeout:  defaultCfg.ReadTimeout,
+			WriteTimeout: defaultCfg.WriteTimeout,
+			IdleTimeout:  defaultCfg.IdleTimeout,
+			ErrorLog:     slog.NewLogLogger(defaultCfg.Logger.Handler(), slog.LevelError),
+		},
+	}
+}
+
+// Application is the server application that wires storages, controllers and routes
+type Application struct {
+	// cfgDb is the database configuration
+	cfgDb *config.ConfigDb
+	// dbTimeout is the maximum duration of the database operations of a request
+	dbTimeout time.Duration
+	// shutdownTimeout is the maximum duration to drain the in-flight requests on shutdown
+	shutdownTimeout time.Duration
+	// logger is the logger of the requests, the server errors and the address it listens on
+	logger *slog.Logger
+	// server is the http server
+	server *http.Server
+	// db is the database connection shared by the storages
+	db *sql.DB
+	// router is the http router
+	router *http.ServeMux
+}
+
+// SetUp opens the database connection and registers the dependencies and routes
+func (a *Application) SetUp() (err error) {
+	// dependencies
+	// - database (none with the json driver, whose storages open the files of the directory)
+	if a.cfgDb == nil {
+		err = fmt.Errorf("application: missing database configuration")
+		return
+	}
+	if a.cfgDb.Driver != config.DriverJSON {
+		a.db, err = a.cfgDb.Open()
+		if err != nil {
+			return
+		}
+		err = a.db.Ping()
+		if err != nil {
+			return
+		}
+	}
+
+	// - storages and use cases of the driver
+	var (
+		stCustomer customersStorage.StorageCustomer
+		stProduct  productsStorage.StorageProduct
+		stInvoice  invoicesStorage.StorageInvoice
+		stSale     salesStorage.StorageSale
+		stAPIKey   apiKeysStorage.StorageAPIKey
+		ucCheckout checkout.Checkout
+		chHealth   health.Checker
+	)
+	switch a.cfgDb.Driver {
+	case config.DriverJSON:
+		dir := a.cfgDb.Path
+		stCustomer = customersStorage.NewStorageCustomerJSON(filepath.Join(dir, jsondb.FileCustomers))
+		stProduct = productsStorage.NewStorageProductJSON(filepath.Join(dir, jsondb.FileProducts))
+		stInvoice = invoicesStorage.NewStorageInvoiceJSON(filepath.Join(dir, jsondb.FileInvoices))
+		stSale = salesStorage.NewStorageSaleJSON(filepath.Join(dir, jsondb.FileSales))
+		stAPIKey = apiKeysStorage.NewStorageAPIKeyJSON(filepath.Join(dir, jsondb.FileAPIKeys))
+		ucCheckout = checkout.NewCheckoutJSON(dir)
+		chHealth = health.NewCheckerJSON(dir, health.Tables)
+	case config.DriverSQLite:
+		stCustomer = customersStorage.NewStorageCustomerSQLite(a.db)
+		stProduct = productsStorage.NewStorageProductSQLite(a.db)
+		stInvoice = invoicesStorage.NewStorageInvoiceSQLite(a.db)
+		stSale = salesStorage.NewStorageSaleSQLite(a.db)
+		stAPIKey = apiKeysStorage.NewStorageAPIKeySQLite(a.db)
+		ucCheckout = checkout.NewCheckoutSQLite(a.db)
+		chHealth = health.NewCheckerSQL(a.db, health.Tables)
+	default:
+		stCustomer = customersStorage.NewStorageCustomerMySQL(a.db)
+		stProduct = productsStorage.NewStorageProductMySQL(a.db)
+		stInvoice = invoicesStorage.NewStorageInvoiceMySQL(a.db)
+		stSale = salesStorage.NewStorageSaleMySQL(a.db)
+		stAPIKey = apiKeysStorage.NewStorageAPIKeyMySQL(a.db)
+		ucCheckout = checkout.NewCheckoutMySQL(a.db)
+		chHealth = health.NewCheckerSQL(a.db, health.Tables)
+	}
+	auAPIKey := auth.NewAuthenticatorAPIKey(stAPIKey)
+
+	// - controllers
+	ctCustomer := handlers.NewControllerCustomer(stCustomer)
+	ctProduct := handlers.NewControllerProduct(stProduct)
+	ctInvoice := handlers.NewControllerInvoice(stInvoice)
+	ctSale := handlers.NewControllerSale(stSale)
+	ctCheckout := handlers.NewControllerCheckout(ucCheckout)
+	ctHealth := handlers.NewControllerHealth(chHealth)
+	ctAuth := handlers.NewControllerAuth(auAPIKey)
+	// - roles (a role allows the routes of the lower roles too)
+	reader := ctAuth.Require(auth.RoleReader)
+	cashier := ctAuth.Require(auth.RoleCashier)
+	admin := ctAuth.Require(auth.RoleAdmin)
+
+	// router
+	a.router = http.NewServeMux()
+	// - health (public, for the orchestrator)
+	a.router.Handle("GET /healthz", ctHealth.Liveness())
+	a.router.Handle("GET /readyz", ctHealth.Readiness())
+	// - customers
+	a.router.Handle("GET /customers", reader(ctCustomer.GetAll()))
+	a.router.Handle("GET /customers/{id}", reader(ctCustomer.GetById()))
+	a.router.Handle("POST /customers", cashier(ctCustomer.Create()))
+	a.router.Handle("PUT /customers/{id}", admin(ctCustomer.Update()))
+	a.router.Handle("PATCH /customers/{id}", admin(ctCustomer.UpdatePartial()))
+	a.router.Handle("DELETE /customers/{id}", admin(ctCustomer.Delete()))
+	a.router.Handle("GET /customers/report/condition", reader(ctCustomer.GetTotalByCondition()))
+	a.router.Handle("GET /customers/top", reader(ctCustomer.GetTopSpenders()))
+	// - products
+	a.router.Handle("GET /products", reader(ctProduct.GetAll()))
+	a.router.Handle("GET /products/{id}", reader(ctProduct.GetById()))
+	a.router.Handle("POST /products", admin(ctProduct.Create()))
+	a.router.Handle("PUT /products/{id}", admin(ctProduct.Update()))
+	a.router.Handle("PATCH /products/{id}", admin(ctProduct.UpdatePartial()))
+	a.router.Handle("DELETE /products/{id}", admin(ctProduct.Delete()))
+	a.router.Handle("GET /products/top", reader(ctProduct.GetTopSold()))
+	// - invoices
+	a.router.Handle("GET /invoices", reader(ctInvoice.GetAll()))
+	a.router.Handle("GET /invoices/{id}", reader(ctInvoice.GetById()))
+	a.router.Handle("POST /invoices", cashier(ctInvoice.Create()))
+	a.router.Handle("PUT /invoices/{id}", admin(ctInvoice.Update()))
+	a.router.Handle("PATCH /invoices/{id}", admin(ctInvoice.UpdatePartial()))
+	a.router.Handle("DELETE /invoices/{id}", admin(ctInvoice.Delete()))
+	a.router.Handle("PATCH /invoices/totals", admin(ctInvoice.UpdateTotals()))
+	a.router.Handle("POST /invoices/checkout", cashier(ctCheckout.Create()))
+	// - sales
+	a.router.Handle("GET /sales", reader(ctSale.GetAll()))
+	a.router.Handle("GET /sales/{id}", reader(ctSale.GetById()))
+	a.router.Handle("POST /sales", cashier(ctSale.Create()))
+	a.router.Handle("PUT /sales/{id}", admin(ctSale.Update()))
+	a.router.Handle("PATCH /sales/{id}", admin(ctSale.UpdatePartial()))
+	a.router.Handle("DELETE /sales/{id}", admin(ctSale.Delete()))
+
+	return
+}
+
+var (
+	// ErrApplicationShutdownTimeout is returned by Run when the in-flight requests are not drained before the shutdown deadline
+	ErrApplicationShutdownTimeout = errors.New("application: shutdown deadline exceeded, in-flight requests aborted")
+)
+
+// Run binds the address of the http server and serves until ctx is done. Then it stops accepting connections and waits
+// for the in-flight requests up to the shutdown timeout (the requests still running after it are aborted,
+// cancelling their context so their database operations are rolled back)
+func (a *Application) Run(ctx context.Context) (err error) {
+	a.server.Handler = middleware.Chain(
+		middleware.RequestID(),
+		middleware.Logger(a.logger),
+		middleware.Recovery(a.logger),
+		middleware.Timeout(a.dbTimeout),
+	)(a.router)
+
+	// listen (logged once the address is bound, with the resolved port)
+	ln, err := net.Listen("tcp", a.server.Addr)
+	if err != nil {
+		return
+	}
+	a.logger.Info("server listening", slog.String("addr", ln.Addr().String()))
+
+	// serve
+	errServe := make(chan error, 1)
+	go func() {
+		errServe <- a.server.Serve(ln)
+	}()
+	select {
+	case err = <-errServe:
+		// the server stopped serving
+		return
+	case <-ctx.Done():
+	}
+
+	// shutdown
+	ctxShutdown, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+	if err = a.server.Shutdown(ctxShutdown); err != nil {
+		a.server.Close()
+		err = fmt.Errorf("%w. %v", ErrApplicationShutdownTimeout, err)
+		return
+	}
+
+	return
+}
+
+// TearDown releases the resources of the application
+func (a *Application) TearDown() (err error) {
+	if a.db != nil {
+		err = a.db.Close()
+	}
+	return
+}
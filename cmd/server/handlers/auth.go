@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"app/internal/auth"
+	"app/pkg/web/middleware"
+	"app/pkg/web/response"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// HeaderAPIKey is the header of the api key of a request (an Authorization: Bearer header is accepted too)
+const HeaderAPIKey = "X-API-Key"
+
+// NewControllerAuth is a constructor for the auth controller
+func NewControllerAuth(au auth.Authenticator) *ControllerAuth {
+	return &ControllerAuth{au: au}
+}
+
+// ControllerAuth is an auth controller that returns middlewares
+type ControllerAuth struct {
+	au auth.Authenticator
+}
+
+// Require returns a middleware that lets through the requests with an api key whose role allows role
+// (401 without a valid key, 403 with a key of a lower role), with the principal in the request context
+type ResponseBodyAuth struct {
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+	Error   bool   `json:"error"`
+}
+func (ct *ControllerAuth) Require(role auth.Role) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// request
+			key := apiKey(r)
+			if key == "" {
+				code := http.StatusUnauthorized
+				body := &ResponseBodyAuth{Message: "Missing API key, set the " + HeaderAPIKey + " header", Data: nil, Error: true}
+
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				response.JSON(w, code, body)
+				return
+			}
+
+			// process
+			p, err := ct.au.Authenticate(r.Context(), key)
+			if err != nil {
+				if errors.Is(err, auth.ErrAuthKeyInvalid) {
+					code := http.StatusUnauthorized
+					body := &ResponseBodyAuth{Message: "Invalid API key", Data: nil, Error: true}
+
+					w.Header().Set("WWW-Authenticate", "Bearer")
+					response.JSON(w, code, body)
+					return
+				}
+				code, message := errorResponse(r.Context(), err)
+				body := &ResponseBodyAuth{Message: message, Data: nil, Error: true}
+
+				response.JSON(w, code, body)
+				return
+			}
+			if !p.Role.Allows(role) {
+				code := http.StatusForbidden
+				body := &ResponseBodyAuth{Message: "Forbidden, the API key role " + string(p.Role) + " is not allowed (requires " + string(role) + ")", Data: nil, Error: true}
+
+				response.JSON(w, code, body)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.ContextWithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// apiKey returns the api key of the request, from the X-API-Key header or the Authorization: Bearer header
+func apiKey(r *http.Request) string {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
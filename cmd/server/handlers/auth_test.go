@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	apiKeysStorage "app/internal/apikeys/storage"
+	"app/internal/auth"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stubAuthenticator is an authenticator of a fixed set of keys
+type stubAuthenticator struct {
+	roles map[string]auth.Role
+	err   error
+}
+
+func (a *stubAuthenticator) Authenticate(ctx context.Context, key string) (p *auth.Principal, err error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	role, ok := a.roles[key]
+	if !ok {
+		return nil, auth.ErrAuthKeyInvalid
+	}
+	return &auth.Principal{KeyId: 1, Name: key, Role: role}, nil
+}
+
+// Tests for ControllerAuth.Require method
+func TestControllerAuth_Require(t *testing.T) {
+	type input struct { au *stubAuthenticator; role auth.Role; header string; value string }
+	type output struct { code int; body string; principal *auth.Principal }
+	type testCase struct {
+		name string
+		input input
+		output output
+	}
+
+	au := &stubAuthenticator{roles: map[string]auth.Role{"k-reader": auth.RoleReader, "k-admin": auth.RoleAdmin}}
+	cases := []testCase{
+		{
+			name: "allowed role",
+			input: input{au: au, role: auth.RoleReader, header: HeaderAPIKey, value: "k-reader"},
+			output: output{code: http.StatusOK, principal: &auth.Principal{KeyId: 1, Name: "k-reader", Role: auth.RoleReader}},
+		},
+		{
+			name: "higher role with bearer token",
+			input: input{au: au, role: auth.RoleCashier, header: "Authorization", value: "Bearer k-admin"},
+			output: output{code: http.StatusOK, principal: &auth.Principal{KeyId: 1, Name: "k-admin", Role: auth.RoleAdmin}},
+		},
+		{
+			name: "missing key",
+			input: input{au: au, role: auth.RoleReader},
+			output: output{code: http.StatusUnauthorized, body: `{"message":"Missing API key, set the X-API-Key header","data":null,"error":true}`},
+		},
+		{
+			name: "invalid key",
+			input: input{au: au, role: auth.RoleReader, header: HeaderAPIKey, value: "k-unknown"},
+			output: output{code: http.StatusUnauthorized, body: `{"message":"Invalid API key","data":null,"error":true}`},
+		},
+		{
+			name: "lower role",
+			input: input{au: au, role: auth.RoleAdmin, header: HeaderAPIKey, value: "k-reader"},
+			output: output{code: http.StatusForbidden, body: `{"message":"Forbidden, the API key role reader is not allowed (requires admin)","data":null,"error":true}`},
+		},
+		{
+			name: "storage error",
+			input: input{au: &stubAuthenticator{err: fmt.Errorf("%w. %v", apiKeysStorage.ErrStorageAPIKeyInternal, "connection refused")}, role: auth.RoleReader, header: HeaderAPIKey, value: "k-reader"},
+			output: output{code: http.StatusInternalServerError, body: `{"message":"Internal server error","data":null,"error":true}`},
+		},
+	}
+
+	// run tests
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			var principal *auth.Principal
+			hd := NewControllerAuth(c.input.au).Require(c.input.role)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal = auth.PrincipalFrom(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/customers", nil)
+			if c.input.header != "" {
+				req.Header.Set(c.input.header, c.input.value)
+			}
+			res := httptest.NewRecorder()
+
+			// act
+			hd.ServeHTTP(res, req)
+
+			// assert
+			require.Equal(t, c.output.code, res.Code)
+			if c.output.body != "" {
+				require.JSONEq(t, c.output.body, res.Body.String())
+			}
+			require.Equal(t, c.output.principal, principal)
+		})
+	}
+}
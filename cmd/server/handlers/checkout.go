@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"app/internal/checkout"
+	"app/pkg/web/request"
+	"app/pkg/web/response"
+	"net/http"
+	"time"
+)
+
+// NewControllerCheckout is a constructor for the checkout controller
+func NewControllerCheckout(uc checkout.Checkout) *ControllerCheckout {
+	return &ControllerCheckout{uc: uc}
+}
+
+// ControllerCheckout is a checkout controller that returns handlers
+type ControllerCheckout struct {
+	uc checkout.Checkout
+}
+
+// Create returns a handler for creating an invoice with its sales
+type RequestItemCheckout struct {
+	ProductId int `json:"product_id" validate:"gt=0"`
+	Quantity  int `json:"quantity" validate:"gt=0"`
+}
+type RequestCheckout struct {
+	CustomerId int                    `json:"customer_id" validate:"gt=0"`
+	Datetime   time.Time              `json:"datetime"`
+	Items      []*RequestItemCheckout `json:"items" validate:"required"`
+}
+type SaleResponseCheckout struct {
+	Id        int `json:"id"`
+	Quantity  int `json:"quantity"`
+	ProductId int `json:"product_id"`
+	InvoiceId int `json:"invoice_id"`
+}
+type InvoiceResponseCheckout struct {
+	Id         int                     `json:"id"`
+	Datetime   time.Time               `json:"datetime"`
+	Total      float64                 `json:"total"`
+	CustomerId int                     `json:"customer_id"`
+	Sales      []*SaleResponseCheckout `json:"sales"`
+}
+type ResponseBodyCheckout struct {
+	Message string                   `json:"message"`
+	Data    *InvoiceResponseCheckout `json:"data"`
+	Error   bool                     `json:"error"`
+	Errors  []request.FieldError `json:"errors,omitempty"`
+}
+func (ct *ControllerCheckout) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var reqBody RequestCheckout
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyCheckout{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyCheckout{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		// -> deserialization
+		order := &checkout.Order{CustomerId: reqBody.CustomerId, Datetime: reqBody.Datetime}
+		for _, it := range reqBody.Items {
+			order.Items = append(order.Items, checkout.Item{ProductId: it.ProductId, Quantity: it.Quantity})
+		}
+		receipt, err := ct.uc.Checkout(r.Context(), order)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyCheckout{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyCheckout{Message: "Success", Data: &InvoiceResponseCheckout{
+			Id:         receipt.Invoice.Id,
+			Datetime:   receipt.Invoice.Datetime,
+			Total:      receipt.Invoice.Total,
+			CustomerId: receipt.Invoice.CustomerId,
+			Sales:      make([]*SaleResponseCheckout, 0, len(receipt.Sales)),
+		}, Error: false}
+		for _, sa := range receipt.Sales {
+			body.Data.Sales = append(body.Data.Sales, &SaleResponseCheckout{
+				Id:        sa.Id,
+				Quantity:  sa.Quantity,
+				ProductId: sa.ProductId,
+				InvoiceId: sa.InvoiceId,
+			})
+		}
+
+		response.JSON(w, code, body)
+	}
+}
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"app/internal/customers/storage"
+	"app/internal/query"
 	"app/pkg/web/request"
 	"app/pkg/web/response"
 	"net/http"
+	"strconv"
 )
 
 // NewControllerCustomer is a constructor for the customer controller
@@ -29,18 +31,26 @@ type CustomerResponseGetAll struct {
 type ResponseBodyGetAllCustomers struct {
 	Message string					  `json:"message"`
 	Data    []*CustomerResponseGetAll `json:"data"`
+	Meta    *ResponseMetaPage `json:"meta,omitempty"`
 	Error	bool					  `json:"error"`
 }
 func (ct *ControllerCustomer) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
-		// ...
+		q, err := query.Parse(r.URL.Query(), storage.CustomerFields)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyGetAllCustomers{Message: queryErrorMessage(err), Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
 
 		// process
-		cs, err := ct.storage.ReadAll()
+		cs, total, err := ct.storage.ReadPage(r.Context(), q)
 		if err != nil {
-			code := http.StatusInternalServerError
-			body := &ResponseBodyGetAllCustomers{Message: "Internal server error", Data: nil, Error: true}
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyGetAllCustomers{Message: message, Data: nil, Error: true}
 
 			response.JSON(w, code, body)
 			return
@@ -48,7 +58,7 @@ func (ct *ControllerCustomer) GetAll() http.HandlerFunc {
 
 		// response
 		code := http.StatusOK
-		body := &ResponseBodyGetAllCustomers{Message: "Success", Data: make([]*CustomerResponseGetAll, 0), Error: false}
+		body := &ResponseBodyGetAllCustomers{Message: "Success", Data: make([]*CustomerResponseGetAll, 0), Meta: responseMetaPage(q, total, query.Next(q, total, cs, storage.CustomerValue)), Error: false}
 		for _, c := range cs {
 			body.Data = append(body.Data, &CustomerResponseGetAll{
 				Id: c.Id,
@@ -62,12 +72,59 @@ func (ct *ControllerCustomer) GetAll() http.HandlerFunc {
 	}
 }
 
-// Create returns a handler for creating a customer
-type RequestBodyCreateCustomers struct {
+// GetById returns a handler for getting a customer by id
+type CustomerResponseGetById struct {
+	Id			int    `json:"id"`
 	FirstName	string `json:"first_name"`
 	LastName	string `json:"last_name"`
 	Condition	bool   `json:"condition"`
 }
+type ResponseBodyGetByIdCustomer struct {
+	Message string					 `json:"message"`
+	Data    *CustomerResponseGetById `json:"data"`
+	Error	bool					 `json:"error"`
+}
+func (ct *ControllerCustomer) GetById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyGetByIdCustomer{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		c, err := ct.storage.ReadById(r.Context(), id)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyGetByIdCustomer{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyGetByIdCustomer{Message: "Success", Data: &CustomerResponseGetById{
+			Id: c.Id,
+			FirstName: c.FirstName,
+			LastName: c.LastName,
+			Condition: c.Condition,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// Create returns a handler for creating a customer
+type RequestBodyCreateCustomers struct {
+	FirstName	string `json:"first_name" validate:"required,max=45"`
+	LastName	string `json:"last_name" validate:"required,max=45"`
+	Condition	bool   `json:"condition"`
+}
 type CustomerResponseCreate struct {
 	Id			int    `json:"id"`
 	FirstName	string `json:"first_name"`
@@ -78,14 +135,22 @@ type ResponseBodyCreateCustomers struct {
 	Message string					`json:"message"`
 	Data    *CustomerResponseCreate `json:"data"`
 	Error	bool					`json:"error"`
+	Errors  []request.FieldError `json:"errors,omitempty"`
 }
 func (ct *ControllerCustomer) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
 		var reqBody RequestBodyCreateCustomers
-		if err := request.JSON(r, &reqBody); err != nil {
-			code := http.StatusBadRequest
-			body := &ResponseBodyCreateCustomers{Message: "Bad request", Data: nil, Error: true}
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyCreateCustomers{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyCreateCustomers{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
 
 			response.JSON(w, code, body)
 			return
@@ -98,10 +163,10 @@ func (ct *ControllerCustomer) Create() http.HandlerFunc {
 			LastName: reqBody.LastName,
 			Condition: reqBody.Condition,
 		}
-		err := ct.storage.Create(c)
+		err := ct.storage.Create(r.Context(), c)
 		if err != nil {
-			code := http.StatusInternalServerError
-			body := &ResponseBodyCreateCustomers{Message: "Internal server error", Data: nil, Error: true}
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyCreateCustomers{Message: message, Data: nil, Error: true}
 
 			response.JSON(w, code, body)
 			return
@@ -116,6 +181,288 @@ func (ct *ControllerCustomer) Create() http.HandlerFunc {
 			Condition: c.Condition,
 		}, Error: false}
 
+		response.JSON(w, code, body)
+	}
+}
+
+// Update returns a handler for replacing a customer
+type RequestBodyUpdateCustomer struct {
+	FirstName string `json:"first_name" validate:"required,max=45"`
+	LastName  string `json:"last_name" validate:"required,max=45"`
+	Condition bool   `json:"condition"`
+}
+type CustomerResponseUpdate struct {
+	Id        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Condition bool   `json:"condition"`
+}
+type ResponseBodyUpdateCustomer struct {
+	Message string			   `json:"message"`
+	Data    *CustomerResponseUpdate `json:"data"`
+	Error	bool			   `json:"error"`
+	Errors  []request.FieldError `json:"errors,omitempty"`
+}
+func (ct *ControllerCustomer) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyUpdateCustomer{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		var reqBody RequestBodyUpdateCustomer
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyUpdateCustomer{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyUpdateCustomer{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		// -> deserialization
+		c := &storage.Customer{
+			Id: id,
+			FirstName: reqBody.FirstName,
+			LastName:  reqBody.LastName,
+			Condition: reqBody.Condition,
+		}
+		if err := ct.storage.Update(r.Context(), c); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateCustomer{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyUpdateCustomer{Message: "Success", Data: &CustomerResponseUpdate{
+			Id:        c.Id,
+			FirstName: c.FirstName,
+			LastName:  c.LastName,
+			Condition: c.Condition,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// UpdatePartial returns a handler for updating some fields of a customer (the fields not in the body are kept)
+func (ct *ControllerCustomer) UpdatePartial() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyUpdateCustomer{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		// -> current customer
+		c, err := ct.storage.ReadById(r.Context(), id)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateCustomer{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		// -> patch the current values with the request body
+		reqBody := RequestBodyUpdateCustomer{
+			FirstName: c.FirstName,
+			LastName:  c.LastName,
+			Condition: c.Condition,
+		}
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyUpdateCustomer{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyUpdateCustomer{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
+
+			response.JSON(w, code, body)
+			return
+		}
+		// -> deserialization
+		c.FirstName = reqBody.FirstName
+		c.LastName = reqBody.LastName
+		c.Condition = reqBody.Condition
+		if err := ct.storage.Update(r.Context(), c); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateCustomer{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyUpdateCustomer{Message: "Success", Data: &CustomerResponseUpdate{
+			Id:        c.Id,
+			FirstName: c.FirstName,
+			LastName:  c.LastName,
+			Condition: c.Condition,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// Delete returns a handler for deleting a customer
+type ResponseBodyDeleteCustomer struct {
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+	Error	bool   `json:"error"`
+}
+func (ct *ControllerCustomer) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyDeleteCustomer{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		if err := ct.storage.Delete(r.Context(), id); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyDeleteCustomer{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusNoContent
+		response.JSON(w, code, nil)
+	}
+}
+
+// GetTotalByCondition returns a handler for getting the amount invoiced grouped by customer condition
+type CustomerResponseTotalByCondition struct {
+	Condition	bool	`json:"condition"`
+	Total		float64	`json:"total"`
+}
+type ResponseBodyTotalByConditionCustomers struct {
+	Message string								`json:"message"`
+	Data    []*CustomerResponseTotalByCondition `json:"data"`
+	Error	bool								`json:"error"`
+}
+func (ct *ControllerCustomer) GetTotalByCondition() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// ...
+
+		// process
+		ts, err := ct.storage.ReadTotalByCondition(r.Context())
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyTotalByConditionCustomers{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyTotalByConditionCustomers{Message: "Success", Data: make([]*CustomerResponseTotalByCondition, 0), Error: false}
+		for _, t := range ts {
+			body.Data = append(body.Data, &CustomerResponseTotalByCondition{
+				Condition: t.Condition,
+				Total: t.Total,
+			})
+		}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// GetTopSpenders returns a handler for getting the customers that spent the most
+// (query params limit, default 5, and condition, active or inactive)
+type CustomerResponseTopSpenders struct {
+	FirstName	string	`json:"first_name"`
+	LastName	string	`json:"last_name"`
+	Amount		float64	`json:"amount"`
+}
+type ResponseBodyTopSpendersCustomers struct {
+	Message string						   `json:"message"`
+	Data    []*CustomerResponseTopSpenders `json:"data"`
+	Error	bool						   `json:"error"`
+}
+func (ct *ControllerCustomer) GetTopSpenders() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		limit := 5
+		if v := r.URL.Query().Get("limit"); v != "" {
+			var err error
+			limit, err = strconv.Atoi(v)
+			if err != nil || limit <= 0 {
+				code := http.StatusBadRequest
+				body := &ResponseBodyTopSpendersCustomers{Message: "Invalid limit, must be a positive integer", Data: nil, Error: true}
+
+				response.JSON(w, code, body)
+				return
+			}
+		}
+		var condition *bool
+		switch r.URL.Query().Get("condition") {
+		case "":
+		case "active":
+			condition = new(bool)
+			*condition = true
+		case "inactive":
+			condition = new(bool)
+		default:
+			code := http.StatusBadRequest
+			body := &ResponseBodyTopSpendersCustomers{Message: "Invalid condition, must be active or inactive", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		cs, err := ct.storage.ReadTopSpenders(r.Context(), limit, condition)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyTopSpendersCustomers{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyTopSpendersCustomers{Message: "Success", Data: make([]*CustomerResponseTopSpenders, 0), Error: false}
+		for _, c := range cs {
+			body.Data = append(body.Data, &CustomerResponseTopSpenders{
+				FirstName: c.FirstName,
+				LastName: c.LastName,
+				Amount: c.Amount,
+			})
+		}
+
 		response.JSON(w, code, body)
 	}
 }
\ No newline at end of file
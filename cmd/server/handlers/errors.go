@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"app/internal/checkout"
+	customersStorage "app/internal/customers/storage"
+	invoicesStorage "app/internal/invoices/storage"
+	productsStorage "app/internal/products/storage"
+	salesStorage "app/internal/sales/storage"
+	"app/pkg/web/request"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// statusClientClosedRequest is the non-standard status code (used by nginx) of a request closed by the client
+const statusClientClosedRequest = 499
+
+// errorResponse maps a storage error to the http status code and the message returned to the client
+// - database operations that exceed the request deadline are 504
+// - database operations canceled because the client closed the request are 499 (not a server error)
+// - invalid checkout orders are 400
+// - relation errors (a foreign key that does not reference an existing record) are 422
+// - referenced errors (a record that can not be deleted because others reference it) are 409
+// - not found errors are 404
+// - any other error is 500
+//
+// Server errors (5xx) are logged with the request context (so the record has the request id),
+// since the client only receives the generic message
+func errorResponse(ctx context.Context, err error) (code int, message string) {
+	defer func() {
+		if code >= http.StatusInternalServerError {
+			slog.ErrorContext(ctx, "request failed", slog.Int("status", code), slog.Any("error", err))
+		}
+	}()
+
+	switch {
+	// timeout and cancellation
+	case errors.Is(err, context.DeadlineExceeded):
+		code, message = http.StatusGatewayTimeout, "Database timeout, try again later"
+	case errors.Is(err, context.Canceled):
+		code, message = statusClientClosedRequest, "Request canceled"
+	// checkout
+	case errors.Is(err, checkout.ErrCheckoutInvalid):
+		code, message = http.StatusBadRequest, "Invalid order: items must not be empty and every quantity must be positive"
+	// relations
+	case errors.Is(err, salesStorage.ErrStorageSaleRelationProduct):
+		code, message = http.StatusUnprocessableEntity, "Sale relation failed: product_id does not reference an existing product"
+	case errors.Is(err, salesStorage.ErrStorageSaleRelationInvoice):
+		code, message = http.StatusUnprocessableEntity, "Sale relation failed: invoice_id does not reference an existing invoice"
+	case errors.Is(err, salesStorage.ErrStorageSaleRelation):
+		code, message = http.StatusUnprocessableEntity, "Sale relation failed: product_id or invoice_id does not reference an existing record"
+	case errors.Is(err, invoicesStorage.ErrStorageInvoiceRelation):
+		code, message = http.StatusUnprocessableEntity, "Invoice relation failed: customer_id does not reference an existing customer"
+	// referenced (foreign key restrict on delete)
+	case errors.Is(err, customersStorage.ErrStorageCustomerReferenced):
+		code, message = http.StatusConflict, "Customer is referenced by invoices and can not be deleted"
+	case errors.Is(err, productsStorage.ErrStorageProductReferenced):
+		code, message = http.StatusConflict, "Product is referenced by sales and can not be deleted"
+	case errors.Is(err, invoicesStorage.ErrStorageInvoiceReferenced):
+		code, message = http.StatusConflict, "Invoice is referenced by sales and can not be deleted"
+	// not found
+	case errors.Is(err, customersStorage.ErrStorageCustomerNotFound):
+		code, message = http.StatusNotFound, "Customer not found"
+	case errors.Is(err, productsStorage.ErrStorageProductNotFound):
+		code, message = http.StatusNotFound, "Product not found"
+	case errors.Is(err, invoicesStorage.ErrStorageInvoiceNotFound):
+		code, message = http.StatusNotFound, "Invoice not found"
+	case errors.Is(err, salesStorage.ErrStorageSaleNotFound):
+		code, message = http.StatusNotFound, "Sale not found"
+	// internal
+	default:
+		code, message = http.StatusInternalServerError, "Internal server error"
+	}
+	return
+}
+
+// bodyErrorResponse maps an error of request.JSON to the http status code and the message returned to the client
+// - bodies larger than the limit are 413
+// - bodies not sent as application/json are 415
+// - any other error (malformed json, unknown fields, trailing data) is 400
+func bodyErrorResponse(err error) (code int, message string) {
+	switch {
+	case errors.Is(err, request.ErrRequestJSONTooLarge):
+		code, message = http.StatusRequestEntityTooLarge, "Request body too large"
+	case errors.Is(err, request.ErrRequestJSONContentType):
+		code, message = http.StatusUnsupportedMediaType, "Unsupported content type, must be application/json"
+	case errors.Is(err, request.ErrRequestJSONUnknownField):
+		code, message = http.StatusBadRequest, "Invalid request body: unknown field " + strings.TrimPrefix(err.Error(), request.ErrRequestJSONUnknownField.Error()+" ")
+	case errors.Is(err, request.ErrRequestJSONTrailingData):
+		code, message = http.StatusBadRequest, "Invalid request body: must be a single json object"
+	default:
+		code, message = http.StatusBadRequest, "Invalid request body"
+	}
+	return
+}
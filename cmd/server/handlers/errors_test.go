@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"app/internal/checkout"
+	customersStorage "app/internal/customers/storage"
+	invoicesStorage "app/internal/invoices/storage"
+	productsStorage "app/internal/products/storage"
+	salesStorage "app/internal/sales/storage"
+	"app/pkg/web/middleware"
+	"app/pkg/web/request"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for errorResponse function
+func TestErrorResponse(t *testing.T) {
+	type input struct { err error }
+	type output struct { code int; message string }
+	type testCase struct {
+		name string
+		input input
+		output output
+	}
+
+	cases := []testCase{
+		{
+			name: "timeout",
+			input: input{err: fmt.Errorf("%w. %w", productsStorage.ErrStorageProductInternal, context.DeadlineExceeded)},
+			output: output{code: http.StatusGatewayTimeout, message: "Database timeout, try again later"},
+		},
+		{
+			name: "canceled by the client",
+			input: input{err: fmt.Errorf("%w. %w", productsStorage.ErrStorageProductInternal, context.Canceled)},
+			output: output{code: statusClientClosedRequest, message: "Request canceled"},
+		},
+		{
+			name: "checkout invalid",
+			input: input{err: checkout.ErrCheckoutInvalid},
+			output: output{code: http.StatusBadRequest, message: "Invalid order: items must not be empty and every quantity must be positive"},
+		},
+		{
+			name: "sale relation - product",
+			input: input{err: fmt.Errorf("%w. %v", salesStorage.ErrStorageSaleRelationProduct, "mysql error 1452")},
+			output: output{code: http.StatusUnprocessableEntity, message: "Sale relation failed: product_id does not reference an existing product"},
+		},
+		{
+			name: "sale relation - invoice",
+			input: input{err: fmt.Errorf("%w. %v", salesStorage.ErrStorageSaleRelationInvoice, "mysql error 1452")},
+			output: output{code: http.StatusUnprocessableEntity, message: "Sale relation failed: invoice_id does not reference an existing invoice"},
+		},
+		{
+			name: "sale relation - unknown constraint",
+			input: input{err: salesStorage.ErrStorageSaleRelation},
+			output: output{code: http.StatusUnprocessableEntity, message: "Sale relation failed: product_id or invoice_id does not reference an existing record"},
+		},
+		{
+			name: "invoice relation",
+			input: input{err: fmt.Errorf("%w. %v", invoicesStorage.ErrStorageInvoiceRelation, "mysql error 1452")},
+			output: output{code: http.StatusUnprocessableEntity, message: "Invoice relation failed: customer_id does not reference an existing customer"},
+		},
+		{
+			name: "referenced",
+			input: input{err: fmt.Errorf("%w. %v", productsStorage.ErrStorageProductReferenced, "mysql error 1451")},
+			output: output{code: http.StatusConflict, message: "Product is referenced by sales and can not be deleted"},
+		},
+		{
+			name: "not found",
+			input: input{err: customersStorage.ErrStorageCustomerNotFound},
+			output: output{code: http.StatusNotFound, message: "Customer not found"},
+		},
+		{
+			name: "internal",
+			input: input{err: fmt.Errorf("%w. %v", salesStorage.ErrStorageSaleInternal, "connection refused")},
+			output: output{code: http.StatusInternalServerError, message: "Internal server error"},
+		},
+		{
+			name: "unknown error",
+			input: input{err: errors.New("unknown")},
+			output: output{code: http.StatusInternalServerError, message: "Internal server error"},
+		},
+	}
+
+	// run tests
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			// ...
+
+			// act
+			code, message := errorResponse(context.Background(), c.input.err)
+
+			// assert
+			require.Equal(t, c.output.code, code)
+			require.Equal(t, c.output.message, message)
+		})
+	}
+}
+
+// Tests for bodyErrorResponse function
+func TestBodyErrorResponse(t *testing.T) {
+	type input struct { err error }
+	type output struct { code int; message string }
+	type testCase struct {
+		name string
+		input input
+		output output
+	}
+
+	cases := []testCase{
+		{
+			name: "too large",
+			input: input{err: fmt.Errorf("%w. limit of %d bytes", request.ErrRequestJSONTooLarge, 1024)},
+			output: output{code: http.StatusRequestEntityTooLarge, message: "Request body too large"},
+		},
+		{
+			name: "content type",
+			input: input{err: fmt.Errorf("%w. %q", request.ErrRequestJSONContentType, "text/plain")},
+			output: output{code: http.StatusUnsupportedMediaType, message: "Unsupported content type, must be application/json"},
+		},
+		{
+			name: "unknown field",
+			input: input{err: fmt.Errorf("%w %s", request.ErrRequestJSONUnknownField, `"price"`)},
+			output: output{code: http.StatusBadRequest, message: `Invalid request body: unknown field "price"`},
+		},
+		{
+			name: "trailing data",
+			input: input{err: fmt.Errorf("%w after offset %d", request.ErrRequestJSONTrailingData, 12)},
+			output: output{code: http.StatusBadRequest, message: "Invalid request body: must be a single json object"},
+		},
+		{
+			name: "malformed",
+			input: input{err: fmt.Errorf("%w. %v", request.ErrRequestJSONInvalid, "unexpected EOF")},
+			output: output{code: http.StatusBadRequest, message: "Invalid request body"},
+		},
+	}
+
+	// run tests
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			// ...
+
+			// act
+			code, message := bodyErrorResponse(c.input.err)
+
+			// assert
+			require.Equal(t, c.output.code, code)
+			require.Equal(t, c.output.message, message)
+		})
+	}
+}
+
+// Tests for the logging of errorResponse function
+func TestErrorResponse_Log(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(middleware.NewRequestIDHandler(slog.NewJSONHandler(&buf, nil))))
+	defer slog.SetDefault(defaultLogger)
+	ctx := middleware.ContextWithRequestID(context.Background(), "req-1")
+
+	t.Run("server error is logged with the request id", func(t *testing.T) {
+		// arrange
+		buf.Reset()
+
+		// act
+		errorResponse(ctx, fmt.Errorf("%w. %v", salesStorage.ErrStorageSaleInternal, "connection refused"))
+
+		// assert
+		require.Contains(t, buf.String(), `"request_id":"req-1"`)
+		require.Contains(t, buf.String(), `"error":"internal storage error. connection refused"`)
+	})
+
+	t.Run("client error is not logged", func(t *testing.T) {
+		// arrange
+		buf.Reset()
+
+		// act
+		errorResponse(ctx, salesStorage.ErrStorageSaleNotFound)
+
+		// assert
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("request canceled by the client is not logged", func(t *testing.T) {
+		// arrange
+		buf.Reset()
+
+		// act
+		errorResponse(ctx, fmt.Errorf("%w. %w", salesStorage.ErrStorageSaleInternal, context.Canceled))
+
+		// assert
+		require.Empty(t, buf.String())
+	})
+}
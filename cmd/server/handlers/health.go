@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"app/internal/health"
+	"app/pkg/web/response"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// NewControllerHealth is a constructor for the health controller
+func NewControllerHealth(ch health.Checker) *ControllerHealth {
+	return &ControllerHealth{ch: ch}
+}
+
+// ControllerHealth is a health controller that returns handlers
+type ControllerHealth struct {
+	ch health.Checker
+}
+
+// Liveness returns a handler that answers while the server is running (it does not check the database)
+type ResponseBodyHealth struct {
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+	Error   bool   `json:"error"`
+}
+func (ct *ControllerHealth) Liveness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyHealth{Message: "Alive", Data: nil, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// Readiness returns a handler that answers 200 when the database is ready and 503 otherwise,
+// with the checks and the connection pool stats
+type ReadinessCheckResponse struct {
+	Ping   string          `json:"ping"`
+	Tables map[string]bool `json:"tables"`
+}
+type ReadinessPoolResponse struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+type ReadinessResponse struct {
+	Ready  bool                    `json:"ready"`
+	Checks *ReadinessCheckResponse `json:"checks"`
+	Pool   *ReadinessPoolResponse  `json:"pool"`
+}
+type ResponseBodyReadiness struct {
+	Message string             `json:"message"`
+	Data    *ReadinessResponse `json:"data"`
+	Error   bool               `json:"error"`
+}
+func (ct *ControllerHealth) Readiness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		s, err := ct.ch.Check(r.Context())
+
+		// response
+		// -> serialization (the error of the ping is only logged, it may expose the database address)
+		ping := "ok"
+		if s.Ping != nil {
+			ping = "unavailable"
+		}
+		data := &ReadinessResponse{
+			Ready:  err == nil,
+			Checks: &ReadinessCheckResponse{Ping: ping, Tables: s.Tables},
+			Pool: &ReadinessPoolResponse{
+				MaxOpenConnections: s.Stats.MaxOpenConnections,
+				OpenConnections:    s.Stats.OpenConnections,
+				InUse:              s.Stats.InUse,
+				Idle:               s.Stats.Idle,
+				WaitCount:          s.Stats.WaitCount,
+				WaitDurationMs:     s.Stats.WaitDuration.Milliseconds(),
+				MaxIdleClosed:      s.Stats.MaxIdleClosed,
+				MaxIdleTimeClosed:  s.Stats.MaxIdleTimeClosed,
+				MaxLifetimeClosed:  s.Stats.MaxLifetimeClosed,
+			},
+		}
+		if err != nil {
+			slog.ErrorContext(r.Context(), "readiness check failed", slog.Any("error", err))
+
+			code, message := http.StatusServiceUnavailable, "Not ready: database ping failed"
+			if errors.Is(err, health.ErrHealthTable) {
+				message = "Not ready: database table missing"
+			}
+			body := &ResponseBodyReadiness{Message: message, Data: data, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		code := http.StatusOK
+		body := &ResponseBodyReadiness{Message: "Ready", Data: data, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
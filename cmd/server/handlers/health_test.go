@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"app/internal/health"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stubChecker is a health checker that returns a fixed status
+type stubChecker struct {
+	s   *health.Status
+	err error
+}
+
+func (c *stubChecker) Check(ctx context.Context) (s *health.Status, err error) {
+	return c.s, c.err
+}
+
+// Tests for ControllerHealth.Readiness method
+func TestControllerHealth_Readiness(t *testing.T) {
+	type input struct { ch *stubChecker }
+	type output struct { code int; body string }
+	type testCase struct {
+		name string
+		input input
+		output output
+	}
+
+	stats := sql.DBStats{MaxOpenConnections: 10, OpenConnections: 2, InUse: 1, Idle: 1}
+	cases := []testCase{
+		{
+			name: "ready",
+			input: input{ch: &stubChecker{s: &health.Status{Tables: map[string]bool{"customers": true}, Stats: stats}}},
+			output: output{code: http.StatusOK, body: `{"message":"Ready","data":{"ready":true,"checks":{"ping":"ok","tables":{"customers":true}},` +
+				`"pool":{"max_open_connections":10,"open_connections":2,"in_use":1,"idle":1,"wait_count":0,"wait_duration_ms":0,` +
+				`"max_idle_closed":0,"max_idle_time_closed":0,"max_lifetime_closed":0}},"error":false}`},
+		},
+		{
+			name: "missing table",
+			input: input{ch: &stubChecker{
+				s:   &health.Status{Tables: map[string]bool{"customers": true, "sales": false}, Stats: stats},
+				err: fmt.Errorf("%w. %v", health.ErrHealthTable, []string{"sales"}),
+			}},
+			output: output{code: http.StatusServiceUnavailable, body: `{"message":"Not ready: database table missing","data":{"ready":false,` +
+				`"checks":{"ping":"ok","tables":{"customers":true,"sales":false}},"pool":{"max_open_connections":10,"open_connections":2,` +
+				`"in_use":1,"idle":1,"wait_count":0,"wait_duration_ms":0,"max_idle_closed":0,"max_idle_time_closed":0,"max_lifetime_closed":0}},"error":true}`},
+		},
+		{
+			name: "ping failed",
+			input: input{ch: &stubChecker{
+				s:   &health.Status{Ping: fmt.Errorf("connection refused"), Tables: map[string]bool{}},
+				err: fmt.Errorf("%w. %v", health.ErrHealthPing, "connection refused"),
+			}},
+			output: output{code: http.StatusServiceUnavailable, body: `{"message":"Not ready: database ping failed","data":{"ready":false,` +
+				`"checks":{"ping":"unavailable","tables":{}},"pool":{"max_open_connections":0,"open_connections":0,` +
+				`"in_use":0,"idle":0,"wait_count":0,"wait_duration_ms":0,"max_idle_closed":0,"max_idle_time_closed":0,"max_lifetime_closed":0}},"error":true}`},
+		},
+	}
+
+	// run tests
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			hd := NewControllerHealth(c.input.ch).Readiness()
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			res := httptest.NewRecorder()
+
+			// act
+			hd(res, req)
+
+			// assert
+			require.Equal(t, c.output.code, res.Code)
+			require.JSONEq(t, c.output.body, res.Body.String())
+		})
+	}
+}
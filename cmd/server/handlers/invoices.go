@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"app/internal/invoices/storage"
+	"app/internal/query"
 	"app/pkg/web/request"
 	"app/pkg/web/response"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -28,18 +32,36 @@ type InvoiceResponseGetAll struct {
 type ResponseBodyGetAllInvoices struct {
 	Message string					 `json:"message"`
 	Data    []*InvoiceResponseGetAll `json:"data"`
+	Meta    *ResponseMetaPage `json:"meta,omitempty"`
 	Error   bool					 `json:"error"`
 }
 func (ct *ControllerInvoice) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
-		// ...
+		v := r.URL.Query()
+		filter, err := invoiceFilter(v)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyGetAllInvoices{Message: queryErrorMessage(err), Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		q, err := query.Parse(v, storage.InvoiceFields)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyGetAllInvoices{Message: queryErrorMessage(err), Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
 
 		// process
-		invoices, err := ct.st.ReadAll()
+		q.Filters = append(q.Filters, filter.Filters()...)
+		invoices, total, err := ct.st.ReadPage(r.Context(), q)
 		if err != nil {
-			code := http.StatusInternalServerError
-			body := &ResponseBodyGetAllInvoices{Message: "Internal server error", Data: nil, Error: true}
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyGetAllInvoices{Message: message, Data: nil, Error: true}
 
 			response.JSON(w, code, body)
 			return
@@ -47,7 +69,7 @@ func (ct *ControllerInvoice) GetAll() http.HandlerFunc {
 
 		// response
 		code := http.StatusOK
-		body := &ResponseBodyGetAllInvoices{Message: "Success", Data: make([]*InvoiceResponseGetAll, 0), Error: false}
+		body := &ResponseBodyGetAllInvoices{Message: "Success", Data: make([]*InvoiceResponseGetAll, 0), Meta: responseMetaPage(q, total, query.Next(q, total, invoices, storage.InvoiceValue)), Error: false}
 		for _, inv := range invoices {
 			body.Data = append(body.Data, &InvoiceResponseGetAll{
 				Id:         inv.Id,
@@ -61,12 +83,132 @@ func (ct *ControllerInvoice) GetAll() http.HandlerFunc {
 	}
 }
 
-// Create returns a handler for creating an invoice
-type RequestCreateInvoice struct {
+// invoiceFilter returns the filter of the invoice parameters of v, removing them from v:
+//   - customer_id: id of the customer
+//   - from, to: range of datetimes (both inclusive, a date without time covers the whole day)
+//   - min_total, max_total: range of totals (both inclusive)
+func invoiceFilter(v url.Values) (f storage.InvoiceFilter, err error) {
+	defer func() {
+		for _, key := range []string{"customer_id", "from", "to", "min_total", "max_total"} {
+			v.Del(key)
+		}
+	}()
+
+	// customer
+	if s := v.Get("customer_id"); s != "" {
+		f.CustomerId, err = strconv.Atoi(s)
+		if err != nil || f.CustomerId <= 0 {
+			err = fmt.Errorf("%w. customer_id: %q is not a positive integer", query.ErrQueryInvalid, s)
+			return
+		}
+	}
+
+	// datetime
+	if s := v.Get("from"); s != "" {
+		f.From, _, err = query.ParseTime(s)
+		if err != nil {
+			err = fmt.Errorf("%w. from: %v", query.ErrQueryInvalid, err)
+			return
+		}
+	}
+	if s := v.Get("to"); s != "" {
+		var layout string
+		f.To, layout, err = query.ParseTime(s)
+		if err != nil {
+			err = fmt.Errorf("%w. to: %v", query.ErrQueryInvalid, err)
+			return
+		}
+		// the filter end is exclusive: the next day for a date, the next second for a datetime
+		if layout == query.DateLayout {
+			f.To = f.To.AddDate(0, 0, 1)
+		} else {
+			f.To = f.To.Add(time.Second)
+		}
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		err = fmt.Errorf("%w. from must be before to", query.ErrQueryInvalid)
+		return
+	}
+
+	// total
+	totals := []struct {
+		key string
+		ptr **float64
+	}{{"min_total", &f.MinTotal}, {"max_total", &f.MaxTotal}}
+	for _, t := range totals {
+		s := v.Get(t.key)
+		if s == "" {
+			continue
+		}
+		var total float64
+		total, err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			err = fmt.Errorf("%w. %s: %q is not a number", query.ErrQueryInvalid, t.key, s)
+			return
+		}
+		*t.ptr = &total
+	}
+	if f.MinTotal != nil && f.MaxTotal != nil && *f.MinTotal > *f.MaxTotal {
+		err = fmt.Errorf("%w. min_total must not be greater than max_total", query.ErrQueryInvalid)
+		return
+	}
+
+	return
+}
+
+// GetById returns a handler for getting an invoice by id
+type InvoiceResponseGetById struct {
+	Id         int       `json:"id"`
 	Datetime   time.Time `json:"datetime"`
 	Total      float64   `json:"total"`
 	CustomerId int       `json:"customer_id"`
 }
+type ResponseBodyGetByIdInvoice struct {
+	Message string					 `json:"message"`
+	Data    *InvoiceResponseGetById `json:"data"`
+	Error	bool					 `json:"error"`
+}
+func (ct *ControllerInvoice) GetById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyGetByIdInvoice{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		inv, err := ct.st.ReadById(r.Context(), id)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyGetByIdInvoice{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyGetByIdInvoice{Message: "Success", Data: &InvoiceResponseGetById{
+			Id:         inv.Id,
+			Datetime:   inv.Datetime,
+			Total:      inv.Total,
+			CustomerId: inv.CustomerId,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// Create returns a handler for creating an invoice
+type RequestCreateInvoice struct {
+	Datetime   time.Time `json:"datetime" validate:"required"`
+	Total      float64   `json:"total" validate:"min=0"`
+	CustomerId int       `json:"customer_id" validate:"gt=0"`
+}
 type InvoiceResponseCreate struct {
 	Id         int       `json:"id"`
 	Datetime   time.Time `json:"datetime"`
@@ -77,15 +219,23 @@ type ResponseBodyCreateInvoice struct {
 	Message string				   `json:"message"`
 	Data    *InvoiceResponseCreate `json:"data"`
 	Error   bool				   `json:"error"`
+	Errors  []request.FieldError `json:"errors,omitempty"`
 }
 
 func (ct *ControllerInvoice) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
 		var reqBody RequestCreateInvoice
-		if err := request.JSON(r, &reqBody); err != nil {
-			code := http.StatusBadRequest
-			body := &ResponseBodyCreateInvoice{Message: "Invalid request body", Data: nil, Error: true}
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyCreateInvoice{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyCreateInvoice{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
 
 			response.JSON(w, code, body)
 			return
@@ -98,9 +248,9 @@ func (ct *ControllerInvoice) Create() http.HandlerFunc {
 			Total:      reqBody.Total,
 			CustomerId: reqBody.CustomerId,
 		}
-		if err := ct.st.Create(inv); err != nil {
-			code := http.StatusInternalServerError
-			body := &ResponseBodyCreateInvoice{Message: "Internal server error", Data: nil, Error: true}
+		if err := ct.st.Create(r.Context(), inv); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyCreateInvoice{Message: message, Data: nil, Error: true}
 
 			response.JSON(w, code, body)
 			return
@@ -118,3 +268,206 @@ func (ct *ControllerInvoice) Create() http.HandlerFunc {
 		response.JSON(w, code, body)
 	}
 }
+
+// Update returns a handler for replacing an invoice
+type RequestBodyUpdateInvoice struct {
+	Datetime   time.Time `json:"datetime" validate:"required"`
+	Total      float64   `json:"total" validate:"min=0"`
+	CustomerId int       `json:"customer_id" validate:"gt=0"`
+}
+type InvoiceResponseUpdate struct {
+	Id         int       `json:"id"`
+	Datetime   time.Time `json:"datetime"`
+	Total      float64   `json:"total"`
+	CustomerId int       `json:"customer_id"`
+}
+type ResponseBodyUpdateInvoice struct {
+	Message string			   `json:"message"`
+	Data    *InvoiceResponseUpdate `json:"data"`
+	Error	bool			   `json:"error"`
+	Errors  []request.FieldError `json:"errors,omitempty"`
+}
+func (ct *ControllerInvoice) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyUpdateInvoice{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		var reqBody RequestBodyUpdateInvoice
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyUpdateInvoice{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyUpdateInvoice{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		// -> deserialization
+		inv := &storage.Invoice{
+			Id: id,
+			Datetime:   reqBody.Datetime,
+			Total:      reqBody.Total,
+			CustomerId: reqBody.CustomerId,
+		}
+		if err := ct.st.Update(r.Context(), inv); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateInvoice{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyUpdateInvoice{Message: "Success", Data: &InvoiceResponseUpdate{
+			Id:         inv.Id,
+			Datetime:   inv.Datetime,
+			Total:      inv.Total,
+			CustomerId: inv.CustomerId,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// UpdatePartial returns a handler for updating some fields of an invoice (the fields not in the body are kept)
+func (ct *ControllerInvoice) UpdatePartial() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyUpdateInvoice{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		// -> current invoice
+		inv, err := ct.st.ReadById(r.Context(), id)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateInvoice{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		// -> patch the current values with the request body
+		reqBody := RequestBodyUpdateInvoice{
+			Datetime:   inv.Datetime,
+			Total:      inv.Total,
+			CustomerId: inv.CustomerId,
+		}
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyUpdateInvoice{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyUpdateInvoice{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
+
+			response.JSON(w, code, body)
+			return
+		}
+		// -> deserialization
+		inv.Datetime = reqBody.Datetime
+		inv.Total = reqBody.Total
+		inv.CustomerId = reqBody.CustomerId
+		if err := ct.st.Update(r.Context(), inv); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateInvoice{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyUpdateInvoice{Message: "Success", Data: &InvoiceResponseUpdate{
+			Id:         inv.Id,
+			Datetime:   inv.Datetime,
+			Total:      inv.Total,
+			CustomerId: inv.CustomerId,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// Delete returns a handler for deleting an invoice
+type ResponseBodyDeleteInvoice struct {
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+	Error	bool   `json:"error"`
+}
+func (ct *ControllerInvoice) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyDeleteInvoice{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		if err := ct.st.Delete(r.Context(), id); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyDeleteInvoice{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusNoContent
+		response.JSON(w, code, nil)
+	}
+}
+
+// UpdateTotals returns a handler for recomputing the total of every invoice from its sales
+type ResponseBodyUpdateTotalsInvoices struct {
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+	Error   bool   `json:"error"`
+}
+func (ct *ControllerInvoice) UpdateTotals() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// ...
+
+		// process
+		if err := ct.st.UpdateTotals(r.Context()); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateTotalsInvoices{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyUpdateTotalsInvoices{Message: "Invoice totals updated", Data: nil, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"app/internal/invoices/storage"
+	"app/internal/query"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for invoiceFilter function
+func TestInvoiceFilter(t *testing.T) {
+	type input struct{ query string }
+	type output struct {
+		filter  storage.InvoiceFilter
+		message string
+	}
+	type testCase struct {
+		name   string
+		input  input
+		output output
+	}
+
+	minTotal, maxTotal := 10.5, 100.0
+	cases := []testCase{
+		{
+			name:  "customer in a month",
+			input: input{query: "customer_id=3&from=2022-03-01&to=2022-03-31"},
+			output: output{filter: storage.InvoiceFilter{
+				CustomerId: 3,
+				From:       time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC),
+				To:         time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name:  "datetimes and totals",
+			input: input{query: "from=2022-03-01 10:00:00&to=2022-03-01T12:30:00Z&min_total=10.5&max_total=100"},
+			output: output{filter: storage.InvoiceFilter{
+				From:     time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC),
+				To:       time.Date(2022, 3, 1, 12, 30, 1, 0, time.UTC),
+				MinTotal: &minTotal,
+				MaxTotal: &maxTotal,
+			}},
+		},
+		{
+			name:   "bad date",
+			input:  input{query: "from=01/03/2022"},
+			output: output{message: `Invalid query parameters: from: "01/03/2022" is not a date (formats: 2006-01-02T15:04:05Z07:00, 2006-01-02 15:04:05, 2006-01-02)`},
+		},
+		{
+			name:   "invalid date",
+			input:  input{query: "to=2022-02-30"},
+			output: output{message: `Invalid query parameters: to: "2022-02-30" is not a date (formats: 2006-01-02T15:04:05Z07:00, 2006-01-02 15:04:05, 2006-01-02)`},
+		},
+		{
+			name:   "from after to",
+			input:  input{query: "from=2022-04-01&to=2022-03-01"},
+			output: output{message: "Invalid query parameters: from must be before to"},
+		},
+		{
+			name:   "bad customer",
+			input:  input{query: "customer_id=-1"},
+			output: output{message: `Invalid query parameters: customer_id: "-1" is not a positive integer`},
+		},
+		{
+			name:   "bad total",
+			input:  input{query: "min_total=ten"},
+			output: output{message: `Invalid query parameters: min_total: "ten" is not a number`},
+		},
+		{
+			name:   "min total greater than max total",
+			input:  input{query: "min_total=10&max_total=5"},
+			output: output{message: "Invalid query parameters: min_total must not be greater than max_total"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			v, err := url.ParseQuery(c.input.query)
+			require.NoError(t, err)
+
+			// act
+			f, err := invoiceFilter(v)
+
+			// assert
+			if c.output.message != "" {
+				require.ErrorIs(t, err, query.ErrQueryInvalid)
+				require.Equal(t, c.output.message, queryErrorMessage(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.output.filter, f)
+			require.Empty(t, v)
+		})
+	}
+}
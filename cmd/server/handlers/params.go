@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"app/internal/query"
+	"app/pkg/web/request"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	// errPathIdInvalid is returned when the last path parameter is not a positive integer
+	errPathIdInvalid = errors.New("path id invalid")
+)
+
+// pathId returns the id in the last path parameter (example: /products/123)
+func pathId(r *http.Request) (id int, err error) {
+	var param string
+	param, err = request.PathLastParam(r)
+	if err != nil {
+		return
+	}
+
+	id, err = strconv.Atoi(param)
+	if err != nil || id <= 0 {
+		err = errPathIdInvalid
+		return
+	}
+
+	return
+}
+
+// maxBodyBytes is the maximum size of a request body
+const maxBodyBytes = 1 << 20
+
+// jsonOptions are the checks of the request bodies: a single json object of known fields, sent as application/json
+var jsonOptions = []request.Option{
+	request.ContentType(),
+	request.MaxBytes(maxBodyBytes),
+	request.DisallowUnknownFields(),
+	request.SingleObject(),
+}
+
+// ResponseMetaPage is the metadata of the page of a GetAll response
+type ResponseMetaPage struct {
+	// Total is the number of rows that match the filters (in every page)
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	// NextCursor is the value of the after parameter for the next page (empty in the last page)
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// responseMetaPage returns the metadata of the page of the query, with the cursor of the next page (see query.Next)
+func responseMetaPage(q query.Query, total int, next string) *ResponseMetaPage {
+	return &ResponseMetaPage{Total: total, Limit: q.Limit, Offset: q.Offset, NextCursor: next}
+}
+
+// queryErrorMessage returns the response message of an error of query.Parse
+func queryErrorMessage(err error) string {
+	return "Invalid query parameters: " + strings.TrimPrefix(err.Error(), query.ErrQueryInvalid.Error()+". ")
+}
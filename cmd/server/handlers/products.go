@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"app/internal/products/storage"
+	"app/internal/query"
 	"app/pkg/web/request"
 	"app/pkg/web/response"
 	"net/http"
+	"strconv"
 )
 
 // NewControllerProduct is a constructor for the product controller
@@ -26,18 +28,26 @@ type ProductResponseGetAll struct {
 type ResponseBodyGetAllProducts struct {
 	Message string					 `json:"message"`
 	Data    []*ProductResponseGetAll `json:"data"`
+	Meta    *ResponseMetaPage `json:"meta,omitempty"`
 	Error	bool					 `json:"error"`
 }
 func (ct *ControllerProduct) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
-		// ...
+		q, err := query.Parse(r.URL.Query(), storage.ProductFields)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyGetAllProducts{Message: queryErrorMessage(err), Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
 
 		// process
-		ps, err := ct.st.ReadAll()
+		ps, total, err := ct.st.ReadPage(r.Context(), q)
 		if err != nil {
-			code := http.StatusInternalServerError
-			body := &ResponseBodyGetAllProducts{Message: "Internal server error", Data: nil, Error: true}
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyGetAllProducts{Message: message, Data: nil, Error: true}
 
 			response.JSON(w, code, body)
 			return
@@ -45,7 +55,7 @@ func (ct *ControllerProduct) GetAll() http.HandlerFunc {
 
 		// response
 		code := http.StatusOK
-		body := &ResponseBodyGetAllProducts{Message: "Success", Data: make([]*ProductResponseGetAll, 0), Error: false}
+		body := &ResponseBodyGetAllProducts{Message: "Success", Data: make([]*ProductResponseGetAll, 0), Meta: responseMetaPage(q, total, query.Next(q, total, ps, storage.ProductValue)), Error: false}
 		for _, p := range ps {
 			body.Data = append(body.Data, &ProductResponseGetAll{
 				Id: p.Id,
@@ -58,11 +68,56 @@ func (ct *ControllerProduct) GetAll() http.HandlerFunc {
 	}
 }
 
-// Create returns a handler for creating a product
-type RequestCreateProducts struct {
+// GetById returns a handler for getting a product by id
+type ProductResponseGetById struct {
+	Id			int		`json:"id"`
 	Description	string	`json:"description"`
 	Price		float64	`json:"price"`
 }
+type ResponseBodyGetByIdProduct struct {
+	Message string					 `json:"message"`
+	Data    *ProductResponseGetById `json:"data"`
+	Error	bool					 `json:"error"`
+}
+func (ct *ControllerProduct) GetById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyGetByIdProduct{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		p, err := ct.st.ReadById(r.Context(), id)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyGetByIdProduct{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyGetByIdProduct{Message: "Success", Data: &ProductResponseGetById{
+			Id: p.Id,
+			Description: p.Description,
+			Price: p.Price,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// Create returns a handler for creating a product
+type RequestCreateProducts struct {
+	Description	string	`json:"description" validate:"required,max=100"`
+	Price		float64	`json:"price" validate:"gt=0"`
+}
 type ProductResponseCreate struct {
 	Id			int		`json:"id"`
 	Description	string	`json:"description"`
@@ -72,14 +127,22 @@ type ResponseBodyCreateProducts struct {
 	Message string				   `json:"message"`
 	Data    *ProductResponseCreate `json:"data"`
 	Error	bool				   `json:"error"`
+	Errors  []request.FieldError `json:"errors,omitempty"`
 }
 func (ct *ControllerProduct) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
 		var reqBody RequestCreateProducts
-		if err := request.JSON(r, &reqBody); err != nil {
-			code := http.StatusBadRequest
-			body := &ResponseBodyCreateProducts{Message: "Invalid request body", Data: nil, Error: true}
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyCreateProducts{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyCreateProducts{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
 
 			response.JSON(w, code, body)
 			return
@@ -91,9 +154,9 @@ func (ct *ControllerProduct) Create() http.HandlerFunc {
 			Description: reqBody.Description,
 			Price: reqBody.Price,
 		}
-		if err := ct.st.Create(p); err != nil {
-			code := http.StatusInternalServerError
-			body := &ResponseBodyCreateProducts{Message: "Internal server error", Data: nil, Error: true}
+		if err := ct.st.Create(r.Context(), p); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyCreateProducts{Message: message, Data: nil, Error: true}
 
 			response.JSON(w, code, body)
 			return
@@ -107,6 +170,226 @@ func (ct *ControllerProduct) Create() http.HandlerFunc {
 			Price: p.Price,
 		}, Error: false}
 
+		response.JSON(w, code, body)
+	}
+}
+
+// Update returns a handler for replacing a product
+type RequestBodyUpdateProduct struct {
+	Description string  `json:"description" validate:"required,max=100"`
+	Price       float64 `json:"price" validate:"gt=0"`
+}
+type ProductResponseUpdate struct {
+	Id          int     `json:"id"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+}
+type ResponseBodyUpdateProduct struct {
+	Message string			   `json:"message"`
+	Data    *ProductResponseUpdate `json:"data"`
+	Error	bool			   `json:"error"`
+	Errors  []request.FieldError `json:"errors,omitempty"`
+}
+func (ct *ControllerProduct) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyUpdateProduct{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		var reqBody RequestBodyUpdateProduct
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyUpdateProduct{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyUpdateProduct{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		// -> deserialization
+		p := &storage.Product{
+			Id: id,
+			Description: reqBody.Description,
+			Price:       reqBody.Price,
+		}
+		if err := ct.st.Update(r.Context(), p); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateProduct{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyUpdateProduct{Message: "Success", Data: &ProductResponseUpdate{
+			Id:          p.Id,
+			Description: p.Description,
+			Price:       p.Price,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// UpdatePartial returns a handler for updating some fields of a product (the fields not in the body are kept)
+func (ct *ControllerProduct) UpdatePartial() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyUpdateProduct{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		// -> current product
+		p, err := ct.st.ReadById(r.Context(), id)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateProduct{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		// -> patch the current values with the request body
+		reqBody := RequestBodyUpdateProduct{
+			Description: p.Description,
+			Price:       p.Price,
+		}
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyUpdateProduct{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyUpdateProduct{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
+
+			response.JSON(w, code, body)
+			return
+		}
+		// -> deserialization
+		p.Description = reqBody.Description
+		p.Price = reqBody.Price
+		if err := ct.st.Update(r.Context(), p); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateProduct{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyUpdateProduct{Message: "Success", Data: &ProductResponseUpdate{
+			Id:          p.Id,
+			Description: p.Description,
+			Price:       p.Price,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// Delete returns a handler for deleting a product
+type ResponseBodyDeleteProduct struct {
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+	Error	bool   `json:"error"`
+}
+func (ct *ControllerProduct) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyDeleteProduct{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		if err := ct.st.Delete(r.Context(), id); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyDeleteProduct{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusNoContent
+		response.JSON(w, code, nil)
+	}
+}
+
+// GetTopSold returns a handler for getting the best-selling products (query param limit, default 5)
+type ProductResponseTopSold struct {
+	Id			int		`json:"id"`
+	Description	string	`json:"description"`
+	Total		int		`json:"total"`
+}
+type ResponseBodyTopSoldProducts struct {
+	Message string					  `json:"message"`
+	Data    []*ProductResponseTopSold `json:"data"`
+	Error	bool					  `json:"error"`
+}
+func (ct *ControllerProduct) GetTopSold() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		limit := 5
+		if v := r.URL.Query().Get("limit"); v != "" {
+			var err error
+			limit, err = strconv.Atoi(v)
+			if err != nil || limit <= 0 {
+				code := http.StatusBadRequest
+				body := &ResponseBodyTopSoldProducts{Message: "Invalid limit, must be a positive integer", Data: nil, Error: true}
+
+				response.JSON(w, code, body)
+				return
+			}
+		}
+
+		// process
+		ps, err := ct.st.ReadTopSold(r.Context(), limit)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyTopSoldProducts{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyTopSoldProducts{Message: "Success", Data: make([]*ProductResponseTopSold, 0), Error: false}
+		for _, p := range ps {
+			body.Data = append(body.Data, &ProductResponseTopSold{
+				Id: p.Id,
+				Description: p.Description,
+				Total: p.Total,
+			})
+		}
+
 		response.JSON(w, code, body)
 	}
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"app/internal/query"
 	"app/internal/sales/storage"
 	"app/pkg/web/request"
 	"app/pkg/web/response"
@@ -27,18 +28,26 @@ type SaleResponseGetAll struct {
 type ResponseBodyGetAllSales struct {
 	Message string				  `json:"message"`
 	Data    []*SaleResponseGetAll `json:"data"`
+	Meta    *ResponseMetaPage `json:"meta,omitempty"`
 	Error   bool				  `json:"error"`
 }
 func (ct *ControllerSale) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
-		// ...
+		q, err := query.Parse(r.URL.Query(), storage.SaleFields)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyGetAllSales{Message: queryErrorMessage(err), Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
 
 		// process
-		sales, err := ct.st.ReadAll()
+		sales, total, err := ct.st.ReadPage(r.Context(), q)
 		if err != nil {
-			code := http.StatusInternalServerError
-			body := &ResponseBodyGetAllSales{Message: "Internal server error", Data: nil, Error: true}
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyGetAllSales{Message: message, Data: nil, Error: true}
 
 			response.JSON(w, code, body)
 			return
@@ -46,7 +55,7 @@ func (ct *ControllerSale) GetAll() http.HandlerFunc {
 
 		// response
 		code := http.StatusOK
-		body := &ResponseBodyGetAllSales{Message: "Success", Data: make([]*SaleResponseGetAll, 0), Error: false}
+		body := &ResponseBodyGetAllSales{Message: "Success", Data: make([]*SaleResponseGetAll, 0), Meta: responseMetaPage(q, total, query.Next(q, total, sales, storage.SaleValue)), Error: false}
 		for _, sale := range sales {
 			body.Data = append(body.Data, &SaleResponseGetAll{
 				Id:         sale.Id,
@@ -60,12 +69,59 @@ func (ct *ControllerSale) GetAll() http.HandlerFunc {
 	}
 }
 
-// Create returns a handler for creating a sale
-type RequestCreateSale struct {
+// GetById returns a handler for getting a sale by id
+type SaleResponseGetById struct {
+	Id         int `json:"id"`
 	Quantity   int `json:"quantity"`
 	ProductId  int `json:"product_id"`
 	InvoiceId  int `json:"invoice_id"`
 }
+type ResponseBodyGetByIdSale struct {
+	Message string					 `json:"message"`
+	Data    *SaleResponseGetById `json:"data"`
+	Error	bool					 `json:"error"`
+}
+func (ct *ControllerSale) GetById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyGetByIdSale{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		sale, err := ct.st.ReadById(r.Context(), id)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyGetByIdSale{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyGetByIdSale{Message: "Success", Data: &SaleResponseGetById{
+			Id:         sale.Id,
+			Quantity:   sale.Quantity,
+			ProductId:  sale.ProductId,
+			InvoiceId:  sale.InvoiceId,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// Create returns a handler for creating a sale
+type RequestCreateSale struct {
+	Quantity   int `json:"quantity" validate:"gt=0"`
+	ProductId  int `json:"product_id" validate:"gt=0"`
+	InvoiceId  int `json:"invoice_id" validate:"gt=0"`
+}
 type SaleResponseCreate struct {
 	Id         int `json:"id"`
 	Quantity   int `json:"quantity"`
@@ -76,14 +132,22 @@ type ResponseBodyCreateSale struct {
 	Message string              `json:"message"`
 	Data    *SaleResponseCreate `json:"data"`
 	Error   bool                `json:"error"`
+	Errors  []request.FieldError `json:"errors,omitempty"`
 }
 func (ct *ControllerSale) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
 		var reqBody RequestCreateSale
-		if err := request.JSON(r, &reqBody); err != nil {
-			code := http.StatusBadRequest
-			body := &ResponseBodyCreateSale{Message: "Invalid request body", Data: nil, Error: true}
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyCreateSale{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyCreateSale{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
 
 			response.JSON(w, code, body)
 			return
@@ -96,9 +160,9 @@ func (ct *ControllerSale) Create() http.HandlerFunc {
 			ProductId:  reqBody.ProductId,
 			InvoiceId:  reqBody.InvoiceId,
 		}
-		if err := ct.st.Create(sale); err != nil {
-			code := http.StatusInternalServerError
-			body := &ResponseBodyCreateSale{Message: "Internal server error", Data: nil, Error: true}
+		if err := ct.st.Create(r.Context(), sale); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyCreateSale{Message: message, Data: nil, Error: true}
 
 			response.JSON(w, code, body)
 			return
@@ -115,4 +179,179 @@ func (ct *ControllerSale) Create() http.HandlerFunc {
 
 		response.JSON(w, code, body)
 	}
+}
+
+// Update returns a handler for replacing a sale
+type RequestBodyUpdateSale struct {
+	Quantity  int `json:"quantity" validate:"gt=0"`
+	ProductId int `json:"product_id" validate:"gt=0"`
+	InvoiceId int `json:"invoice_id" validate:"gt=0"`
+}
+type SaleResponseUpdate struct {
+	Id        int `json:"id"`
+	Quantity  int `json:"quantity"`
+	ProductId int `json:"product_id"`
+	InvoiceId int `json:"invoice_id"`
+}
+type ResponseBodyUpdateSale struct {
+	Message string			   `json:"message"`
+	Data    *SaleResponseUpdate `json:"data"`
+	Error	bool			   `json:"error"`
+	Errors  []request.FieldError `json:"errors,omitempty"`
+}
+func (ct *ControllerSale) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyUpdateSale{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		var reqBody RequestBodyUpdateSale
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyUpdateSale{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyUpdateSale{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		// -> deserialization
+		sale := &storage.Sale{
+			Id: id,
+			Quantity:  reqBody.Quantity,
+			ProductId: reqBody.ProductId,
+			InvoiceId: reqBody.InvoiceId,
+		}
+		if err := ct.st.Update(r.Context(), sale); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateSale{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyUpdateSale{Message: "Success", Data: &SaleResponseUpdate{
+			Id:        sale.Id,
+			Quantity:  sale.Quantity,
+			ProductId: sale.ProductId,
+			InvoiceId: sale.InvoiceId,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// UpdatePartial returns a handler for updating some fields of a sale (the fields not in the body are kept)
+func (ct *ControllerSale) UpdatePartial() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyUpdateSale{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		// -> current sale
+		sale, err := ct.st.ReadById(r.Context(), id)
+		if err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateSale{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		// -> patch the current values with the request body
+		reqBody := RequestBodyUpdateSale{
+			Quantity:  sale.Quantity,
+			ProductId: sale.ProductId,
+			InvoiceId: sale.InvoiceId,
+		}
+		if err := request.JSON(w, r, &reqBody, jsonOptions...); err != nil {
+			code, message := bodyErrorResponse(err)
+			body := &ResponseBodyUpdateSale{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+		if fields := request.Validate(&reqBody); fields != nil {
+			code := http.StatusUnprocessableEntity
+			body := &ResponseBodyUpdateSale{Message: "Invalid request body fields", Data: nil, Error: true, Errors: fields}
+
+			response.JSON(w, code, body)
+			return
+		}
+		// -> deserialization
+		sale.Quantity = reqBody.Quantity
+		sale.ProductId = reqBody.ProductId
+		sale.InvoiceId = reqBody.InvoiceId
+		if err := ct.st.Update(r.Context(), sale); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyUpdateSale{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusOK
+		body := &ResponseBodyUpdateSale{Message: "Success", Data: &SaleResponseUpdate{
+			Id:        sale.Id,
+			Quantity:  sale.Quantity,
+			ProductId: sale.ProductId,
+			InvoiceId: sale.InvoiceId,
+		}, Error: false}
+
+		response.JSON(w, code, body)
+	}
+}
+
+// Delete returns a handler for deleting a sale
+type ResponseBodyDeleteSale struct {
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+	Error	bool   `json:"error"`
+}
+func (ct *ControllerSale) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := pathId(r)
+		if err != nil {
+			code := http.StatusBadRequest
+			body := &ResponseBodyDeleteSale{Message: "Invalid id, must be a positive integer", Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// process
+		if err := ct.st.Delete(r.Context(), id); err != nil {
+			code, message := errorResponse(r.Context(), err)
+			body := &ResponseBodyDeleteSale{Message: message, Data: nil, Error: true}
+
+			response.JSON(w, code, body)
+			return
+		}
+
+		// response
+		code := http.StatusNoContent
+		response.JSON(w, code, nil)
+	}
 }
\ No newline at end of file
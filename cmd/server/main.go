@@ -1,4 +1,91 @@
 package main
 
+import (
+	"app/internal/config"
+	"app/pkg/web/middleware"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// exit codes of the server
+const (
+	// exitOk is a shutdown that drained every in-flight request
+	exitOk = 0
+	// exitError is a failure to configure, set up or run the server
+	exitError = 1
+	// exitShutdownTimeout is a shutdown whose deadline expired with requests in flight (they were aborted)
+	exitShutdownTimeout = 2
+)
+
 func main() {
-}
\ No newline at end of file
+	os.Exit(run())
+}
+
+// run runs the server until SIGINT or SIGTERM and returns the exit code
+func run() (code int) {
+	// logger (default too, with the request id in the records logged with a request context)
+	logger := slog.New(middleware.NewRequestIDHandler(slog.NewJSONHandler(os.Stdout, nil)))
+	slog.SetDefault(logger)
+
+	// env
+	cfg, err := config.Load(os.LookupEnv)
+	if err != nil {
+		logger.Error("loading the config", slog.Any("error", err))
+		return exitError
+	}
+
+	// context (done on the first SIGINT or SIGTERM, which starts the shutdown). The signals are
+	// restored to their default behavior before the drain starts, so a second one kills the process
+	ctxSignal, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	context.AfterFunc(ctxSignal, func() {
+		stop()
+		cancel()
+	})
+
+	// app
+	// - config
+	app := NewApplication(&ConfigApplication{
+		Db:              &cfg.Db,
+		Addr:            cfg.Server.Addr,
+		ReadTimeout:     cfg.Server.ReadTimeout,
+		WriteTimeout:    cfg.Server.WriteTimeout,
+		IdleTimeout:     cfg.Server.IdleTimeout,
+		DbTimeout:       cfg.Db.QueryTimeout,
+		ShutdownTimeout: cfg.Server.ShutdownTimeout,
+		Logger:          logger,
+	})
+	// - tear down (after the requests are drained, so no storage uses the closed database)
+	defer func() {
+		if err := app.TearDown(); err != nil {
+			logger.Error("closing the database", slog.Any("error", err))
+			if code == exitOk {
+				code = exitError
+			}
+		}
+	}()
+	// - set up
+	if err := app.SetUp(); err != nil {
+		logger.Error("setting up the server", slog.Any("error", err))
+		return exitError
+	}
+	// - run
+	err = app.Run(ctx)
+	switch {
+	case errors.Is(err, ErrApplicationShutdownTimeout):
+		logger.Error("server stopped", slog.Any("error", err))
+		return exitShutdownTimeout
+	case err != nil:
+		logger.Error("running the server", slog.Any("error", err))
+		return exitError
+	}
+	logger.Info("server stopped, in-flight requests drained")
+
+	return exitOk
+}
@@ -48,4 +48,17 @@ CREATE TABLE `sales` (
     KEY `idx_sales_product_id` (`product_id`),
     CONSTRAINT `fk_sales_invoice_id` FOREIGN KEY (`invoice_id`) REFERENCES `invoices` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
     CONSTRAINT `fk_sales_product_id` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
-) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
\ No newline at end of file
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Table: api_keys
+CREATE TABLE `api_keys` (
+    `id` int NOT NULL AUTO_INCREMENT,
+    `name` varchar(100) NOT NULL,
+    `key_hash` char(64) NOT NULL,
+    `role` varchar(20) NOT NULL,
+    `created_at` datetime NOT NULL,
+    `revoked_at` datetime NULL,
+    -- constraints
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_api_keys_key_hash` (`key_hash`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// APIKey is a struct that represents an api key (only the hash of the key is stored)
+type APIKey struct {
+	Id   int
+	Name string
+	// Hash is the hex sha256 of the key
+	Hash string
+	// Role is the role of the requests authenticated with the key (reader, cashier or admin)
+	Role      string
+	CreatedAt time.Time
+	// RevokedAt is when the key was revoked (zero if it is active)
+	RevokedAt time.Time
+}
+
+// StorageAPIKey is an interface that represents an api key storage
+type StorageAPIKey interface {
+	// ReadByHash returns the api key with the given hash
+	ReadByHash(ctx context.Context, hash string) (k *APIKey, err error)
+
+	// Create inserts a new api key
+	Create(ctx context.Context, k *APIKey) (err error)
+
+	// Revoke sets the revocation time of the api key with the given id
+	Revoke(ctx context.Context, id int, at time.Time) (err error)
+}
+
+var (
+	// ErrStorageAPIKeyInternal is returned when an internal error occurs
+	ErrStorageAPIKeyInternal = errors.New("internal storage error")
+	// ErrStorageAPIKeyNotFound is returned when an api key is not found
+	ErrStorageAPIKeyNotFound = errors.New("api key not found")
+)
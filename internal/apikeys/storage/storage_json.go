@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"app/internal/jsondb"
+	"app/internal/memdb"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// NewStorageAPIKeyJSON returns a new instance of StorageAPIKeyJSON for the json file at path.
+// The other tables are read from the files with their canonical names in the same directory (see jsondb.FilesFor)
+func NewStorageAPIKeyJSON(path string) *StorageAPIKeyJSON {
+	return &StorageAPIKeyJSON{db: jsondb.New(jsondb.FilesFor(jsondb.TableAPIKeys, path))}
+}
+
+// StorageAPIKeyJSON is a struct that represents an api key storage in a json file for StorageAPIKey interface.
+// Each operation works on the data loaded from the files, with the same semantics as StorageAPIKeyMap
+type StorageAPIKeyJSON struct {
+	db *jsondb.DB
+}
+
+// ReadByHash returns the api key with the given hash
+func (s *StorageAPIKeyJSON) ReadByHash(ctx context.Context, hash string) (k *APIKey, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		k, err = NewStorageAPIKeyMap(mdb).ReadByHash(ctx, hash)
+		return
+	})
+	err = apiKeyJSONError(err)
+	return
+}
+
+// Create inserts a new api key (the id is kept if set, otherwise it is generated)
+func (s *StorageAPIKeyJSON) Create(ctx context.Context, k *APIKey) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageAPIKeyMap(mdb).Create(ctx, k)
+		return
+	}, jsondb.TableAPIKeys)
+	err = apiKeyJSONError(err)
+	return
+}
+
+// Revoke sets the revocation time of the api key with the given id (a revoked key keeps its first revocation time)
+func (s *StorageAPIKeyJSON) Revoke(ctx context.Context, id int, at time.Time) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageAPIKeyMap(mdb).Revoke(ctx, id, at)
+		return
+	}, jsondb.TableAPIKeys)
+	err = apiKeyJSONError(err)
+	return
+}
+
+// apiKeyJSONError wraps the errors of the json files as internal storage errors
+func apiKeyJSONError(err error) error {
+	if errors.Is(err, jsondb.ErrJSONDBFile) {
+		return fmt.Errorf("%w. %v", ErrStorageAPIKeyInternal, err)
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StorageAPIKeyJSON
+func TestStorageAPIKeyJSON(t *testing.T) {
+	t.Run("create, read and revoke", func(t *testing.T) {
+		// arrange (each operation uses its own storage, as different processes would)
+		path := filepath.Join(t.TempDir(), "api_keys.json")
+		createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		revokedAt := createdAt.Add(time.Hour)
+
+		// act
+		k := &APIKey{Name: "front desk", Hash: "abc", Role: "cashier", CreatedAt: createdAt}
+		errCreate := NewStorageAPIKeyJSON(path).Create(context.Background(), k)
+		errDuplicate := NewStorageAPIKeyJSON(path).Create(context.Background(), &APIKey{Name: "copy", Hash: "abc", Role: "reader", CreatedAt: createdAt})
+		read, errRead := NewStorageAPIKeyJSON(path).ReadByHash(context.Background(), "abc")
+		errRevoke := NewStorageAPIKeyJSON(path).Revoke(context.Background(), k.Id, revokedAt)
+		errRevokeAgain := NewStorageAPIKeyJSON(path).Revoke(context.Background(), k.Id, revokedAt.Add(time.Hour))
+		revoked, _ := NewStorageAPIKeyJSON(path).ReadByHash(context.Background(), "abc")
+
+		// assert
+		require.NoError(t, errCreate)
+		require.Equal(t, 1, k.Id)
+		require.ErrorIs(t, errDuplicate, ErrStorageAPIKeyInternal)
+		require.NoError(t, errRead)
+		require.Equal(t, &APIKey{Id: 1, Name: "front desk", Hash: "abc", Role: "cashier", CreatedAt: createdAt}, read)
+		require.NoError(t, errRevoke)
+		require.NoError(t, errRevokeAgain)
+		require.Equal(t, revokedAt, revoked.RevokedAt)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// arrange
+		st := NewStorageAPIKeyJSON(filepath.Join(t.TempDir(), "api_keys.json"))
+
+		// act
+		_, errRead := st.ReadByHash(context.Background(), "missing")
+		errRevoke := st.Revoke(context.Background(), 1, time.Now())
+
+		// assert
+		require.ErrorIs(t, errRead, ErrStorageAPIKeyNotFound)
+		require.ErrorIs(t, errRevoke, ErrStorageAPIKeyNotFound)
+	})
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"app/internal/memdb"
+	"context"
+	"fmt"
+	"time"
+)
+
+// NewStorageAPIKeyMap returns a new instance of StorageAPIKeyMap
+func NewStorageAPIKeyMap(db *memdb.DB) *StorageAPIKeyMap {
+	return &StorageAPIKeyMap{db}
+}
+
+// StorageAPIKeyMap is a struct that represents an api key storage in memory for StorageAPIKey interface
+type StorageAPIKeyMap struct {
+	db *memdb.DB
+}
+
+// ReadByHash returns the api key with the given hash
+func (s *StorageAPIKeyMap) ReadByHash(ctx context.Context, hash string) (k *APIKey, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		for _, row := range t.APIKeys {
+			if row.KeyHash == hash {
+				k = apiKeyFromRow(row)
+				return
+			}
+		}
+
+		err = ErrStorageAPIKeyNotFound
+		return
+	})
+	return
+}
+
+// Create inserts a new api key (the id is kept if set, otherwise it is generated)
+func (s *StorageAPIKeyMap) Create(ctx context.Context, k *APIKey) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		// check duplicated id and hash
+		if _, ok := t.APIKeys[k.Id]; ok {
+			err = fmt.Errorf("%w. duplicated id %d", ErrStorageAPIKeyInternal, k.Id)
+			return
+		}
+		for _, row := range t.APIKeys {
+			if row.KeyHash == k.Hash {
+				err = fmt.Errorf("%w. duplicated key hash", ErrStorageAPIKeyInternal)
+				return
+			}
+		}
+
+		// insert
+		k.Id = t.APIKeyId(k.Id)
+		t.APIKeys[k.Id] = apiKeyToRow(k)
+		return
+	})
+	return
+}
+
+// Revoke sets the revocation time of the api key with the given id (a revoked key keeps its first revocation time)
+func (s *StorageAPIKeyMap) Revoke(ctx context.Context, id int, at time.Time) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		row, ok := t.APIKeys[id]
+		if !ok {
+			err = ErrStorageAPIKeyNotFound
+			return
+		}
+
+		if row.RevokedAt.IsZero() {
+			row.RevokedAt = at
+		}
+		return
+	})
+	return
+}
+
+// apiKeyFromRow returns an api key with the values of the row
+func apiKeyFromRow(row *memdb.APIKeyRow) *APIKey {
+	return &APIKey{
+		Id:        row.Id,
+		Name:      row.Name,
+		Hash:      row.KeyHash,
+		Role:      row.Role,
+		CreatedAt: row.CreatedAt,
+		RevokedAt: row.RevokedAt,
+	}
+}
+
+// apiKeyToRow returns a row with the values of the api key
+func apiKeyToRow(k *APIKey) *memdb.APIKeyRow {
+	return &memdb.APIKeyRow{
+		Id:        k.Id,
+		Name:      k.Name,
+		KeyHash:   k.Hash,
+		Role:      k.Role,
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+	}
+}
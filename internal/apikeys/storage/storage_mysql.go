@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"app/internal/sqltx"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// NewStorageAPIKeyMySQL returns a new instance of StorageAPIKeyMySQL
+func NewStorageAPIKeyMySQL(db *sql.DB) *StorageAPIKeyMySQL {
+	return &StorageAPIKeyMySQL{db}
+}
+
+// APIKeyMySQL is a struct that represents an api key in MySQL
+type APIKeyMySQL struct {
+	Id        sql.NullInt32
+	Name      sql.NullString
+	KeyHash   sql.NullString
+	Role      sql.NullString
+	CreatedAt sql.NullTime
+	RevokedAt sql.NullTime
+}
+
+// StorageAPIKeyMySQL is a struct that represents an api key storage in MySQL for StorageAPIKey interface
+type StorageAPIKeyMySQL struct {
+	// db is the database or the transaction the operations run on
+	db sqltx.Executor
+}
+
+// ReadByHash returns the api key with the given hash
+func (s *StorageAPIKeyMySQL) ReadByHash(ctx context.Context, hash string) (k *APIKey, err error) {
+	// query
+	query := "SELECT id, name, key_hash, role, created_at, revoked_at FROM api_keys WHERE key_hash = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var kMySQL APIKeyMySQL
+	err = stmt.QueryRowContext(ctx, hash).Scan(&kMySQL.Id, &kMySQL.Name, &kMySQL.KeyHash, &kMySQL.Role, &kMySQL.CreatedAt, &kMySQL.RevokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w. %v", ErrStorageAPIKeyNotFound, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+
+	// serialization
+	k = new(APIKey)
+	if kMySQL.Id.Valid {
+		k.Id = int(kMySQL.Id.Int32)
+	}
+	if kMySQL.Name.Valid {
+		k.Name = kMySQL.Name.String
+	}
+	if kMySQL.KeyHash.Valid {
+		k.Hash = kMySQL.KeyHash.String
+	}
+	if kMySQL.Role.Valid {
+		k.Role = kMySQL.Role.String
+	}
+	if kMySQL.CreatedAt.Valid {
+		k.CreatedAt = kMySQL.CreatedAt.Time
+	}
+	if kMySQL.RevokedAt.Valid {
+		k.RevokedAt = kMySQL.RevokedAt.Time
+	}
+
+	return
+}
+
+// Create inserts a new api key
+func (s *StorageAPIKeyMySQL) Create(ctx context.Context, k *APIKey) (err error) {
+	// deserialization
+	var kMySQL APIKeyMySQL
+	kMySQL.Name.Valid = true
+	kMySQL.Name.String = k.Name
+	kMySQL.KeyHash.Valid = true
+	kMySQL.KeyHash.String = k.Hash
+	kMySQL.Role.Valid = true
+	kMySQL.Role.String = k.Role
+	kMySQL.CreatedAt.Valid = true
+	kMySQL.CreatedAt.Time = k.CreatedAt
+	if !k.RevokedAt.IsZero() {
+		kMySQL.RevokedAt.Valid = true
+		kMySQL.RevokedAt.Time = k.RevokedAt
+	}
+
+	// query
+	query := "INSERT INTO api_keys (name, key_hash, role, created_at, revoked_at) VALUES (?, ?, ?, ?, ?)"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, kMySQL.Name, kMySQL.KeyHash, kMySQL.Role, kMySQL.CreatedAt, kMySQL.RevokedAt)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+
+	// get last insert id
+	var lastInsertId int64
+	lastInsertId, err = result.LastInsertId()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+
+	// set last insert id
+	k.Id = int(lastInsertId)
+
+	return
+}
+
+// Revoke sets the revocation time of the api key with the given id (a revoked key keeps its first revocation time)
+func (s *StorageAPIKeyMySQL) Revoke(ctx context.Context, id int, at time.Time) (err error) {
+	// execute query
+	var result sql.Result
+	result, err = s.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", at, id)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+
+	// check rows affected (0 when the api key does not exist or is already revoked)
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		var exists int
+		err = s.db.QueryRowContext(ctx, "SELECT 1 FROM api_keys WHERE id = ?", id).Scan(&exists)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = fmt.Errorf("%w. %v", ErrStorageAPIKeyNotFound, err)
+				return
+			}
+			err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+			return
+		}
+	}
+
+	return
+}
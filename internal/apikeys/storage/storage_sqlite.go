@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"app/internal/sqltx"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// NewStorageAPIKeySQLite returns a new instance of StorageAPIKeySQLite
+func NewStorageAPIKeySQLite(db *sql.DB) *StorageAPIKeySQLite {
+	return &StorageAPIKeySQLite{db}
+}
+
+// APIKeySQLite is a struct that represents an api key in SQLite
+type APIKeySQLite struct {
+	Id        sql.NullInt32
+	Name      sql.NullString
+	KeyHash   sql.NullString
+	Role      sql.NullString
+	CreatedAt sql.NullTime
+	RevokedAt sql.NullTime
+}
+
+// StorageAPIKeySQLite is a struct that represents an api key storage in SQLite for StorageAPIKey interface
+type StorageAPIKeySQLite struct {
+	// db is the database or the transaction the operations run on
+	db sqltx.Executor
+}
+
+// ReadByHash returns the api key with the given hash
+func (s *StorageAPIKeySQLite) ReadByHash(ctx context.Context, hash string) (k *APIKey, err error) {
+	// query
+	query := "SELECT id, name, key_hash, role, created_at, revoked_at FROM api_keys WHERE key_hash = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var kSQLite APIKeySQLite
+	err = stmt.QueryRowContext(ctx, hash).Scan(&kSQLite.Id, &kSQLite.Name, &kSQLite.KeyHash, &kSQLite.Role, &kSQLite.CreatedAt, &kSQLite.RevokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w. %v", ErrStorageAPIKeyNotFound, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+
+	// serialization
+	k = new(APIKey)
+	if kSQLite.Id.Valid {
+		k.Id = int(kSQLite.Id.Int32)
+	}
+	if kSQLite.Name.Valid {
+		k.Name = kSQLite.Name.String
+	}
+	if kSQLite.KeyHash.Valid {
+		k.Hash = kSQLite.KeyHash.String
+	}
+	if kSQLite.Role.Valid {
+		k.Role = kSQLite.Role.String
+	}
+	if kSQLite.CreatedAt.Valid {
+		k.CreatedAt = kSQLite.CreatedAt.Time
+	}
+	if kSQLite.RevokedAt.Valid {
+		k.RevokedAt = kSQLite.RevokedAt.Time
+	}
+
+	return
+}
+
+// Create inserts a new api key
+func (s *StorageAPIKeySQLite) Create(ctx context.Context, k *APIKey) (err error) {
+	// deserialization
+	var kSQLite APIKeySQLite
+	kSQLite.Name.Valid = true
+	kSQLite.Name.String = k.Name
+	kSQLite.KeyHash.Valid = true
+	kSQLite.KeyHash.String = k.Hash
+	kSQLite.Role.Valid = true
+	kSQLite.Role.String = k.Role
+	kSQLite.CreatedAt.Valid = true
+	kSQLite.CreatedAt.Time = k.CreatedAt
+	if !k.RevokedAt.IsZero() {
+		kSQLite.RevokedAt.Valid = true
+		kSQLite.RevokedAt.Time = k.RevokedAt
+	}
+
+	// query
+	query := "INSERT INTO api_keys (name, key_hash, role, created_at, revoked_at) VALUES (?, ?, ?, ?, ?)"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, kSQLite.Name, kSQLite.KeyHash, kSQLite.Role, kSQLite.CreatedAt, kSQLite.RevokedAt)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+
+	// get last insert id
+	var lastInsertId int64
+	lastInsertId, err = result.LastInsertId()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+
+	// set last insert id
+	k.Id = int(lastInsertId)
+
+	return
+}
+
+// Revoke sets the revocation time of the api key with the given id (a revoked key keeps its first revocation time)
+func (s *StorageAPIKeySQLite) Revoke(ctx context.Context, id int, at time.Time) (err error) {
+	// execute query
+	var result sql.Result
+	result, err = s.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", at, id)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+
+	// check rows affected (0 when the api key does not exist or is already revoked)
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		var exists int
+		err = s.db.QueryRowContext(ctx, "SELECT 1 FROM api_keys WHERE id = ?", id).Scan(&exists)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = fmt.Errorf("%w. %v", ErrStorageAPIKeyNotFound, err)
+				return
+			}
+			err = fmt.Errorf("%w. %w", ErrStorageAPIKeyInternal, err)
+			return
+		}
+	}
+
+	return
+}
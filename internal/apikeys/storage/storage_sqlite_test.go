@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"app/internal/sqlitedb"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteDB returns an in-memory SQLite database with the schema
+func newSQLiteDB(t *testing.T) *sql.DB {
+	db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// Tests for StorageAPIKeySQLite
+func TestStorageAPIKeySQLite(t *testing.T) {
+	t.Run("create, read and revoke", func(t *testing.T) {
+		// arrange
+		st := NewStorageAPIKeySQLite(newSQLiteDB(t))
+		createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		revokedAt := createdAt.Add(time.Hour)
+
+		// act
+		k := &APIKey{Name: "front desk", Hash: "abc", Role: "cashier", CreatedAt: createdAt}
+		errCreate := st.Create(context.Background(), k)
+		read, errRead := st.ReadByHash(context.Background(), "abc")
+		errRevoke := st.Revoke(context.Background(), k.Id, revokedAt)
+		errRevokeAgain := st.Revoke(context.Background(), k.Id, revokedAt.Add(time.Hour))
+		revoked, _ := st.ReadByHash(context.Background(), "abc")
+
+		// assert
+		require.NoError(t, errCreate)
+		require.Equal(t, 1, k.Id)
+		require.NoError(t, errRead)
+		require.Equal(t, k.Hash, read.Hash)
+		require.Equal(t, k.Role, read.Role)
+		require.True(t, createdAt.Equal(read.CreatedAt))
+		require.True(t, read.RevokedAt.IsZero())
+		require.NoError(t, errRevoke)
+		require.NoError(t, errRevokeAgain)
+		require.True(t, revokedAt.Equal(revoked.RevokedAt))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// arrange
+		st := NewStorageAPIKeySQLite(newSQLiteDB(t))
+
+		// act
+		_, errRead := st.ReadByHash(context.Background(), "missing")
+		errRevoke := st.Revoke(context.Background(), 1, time.Now())
+
+		// assert
+		require.ErrorIs(t, errRead, ErrStorageAPIKeyNotFound)
+		require.ErrorIs(t, errRevoke, ErrStorageAPIKeyNotFound)
+	})
+}
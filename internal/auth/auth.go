@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"app/internal/apikeys/storage"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Role is the role of an api key, which sets the operations its requests can do
+type Role string
+
+const (
+	// RoleReader can read customers, products, invoices and sales
+	RoleReader Role = "reader"
+	// RoleCashier can also create sales, invoices and customers
+	RoleCashier Role = "cashier"
+	// RoleAdmin can do every operation (including updates, deletes and the totals recomputation)
+	RoleAdmin Role = "admin"
+)
+
+// roleLevels are the levels of the roles: a role allows the operations of the roles of lower or equal level
+var roleLevels = map[Role]int{
+	RoleReader:  1,
+	RoleCashier: 2,
+	RoleAdmin:   3,
+}
+
+// ParseRole returns the role named s
+func ParseRole(s string) (r Role, err error) {
+	r = Role(s)
+	if _, ok := roleLevels[r]; !ok {
+		err = fmt.Errorf("%w. %q (roles: %s, %s, %s)", ErrAuthRoleInvalid, s, RoleReader, RoleCashier, RoleAdmin)
+		return
+	}
+	return
+}
+
+// Allows reports whether the role can do the operations that require the role required
+func (r Role) Allows(required Role) bool {
+	level, ok := roleLevels[r]
+	return ok && level >= roleLevels[required]
+}
+
+// Principal is a struct that represents the api key a request was authenticated with
+type Principal struct {
+	KeyId int
+	Name  string
+	Role  Role
+}
+
+// Authenticator is an interface that represents the authentication of the api keys
+type Authenticator interface {
+	// Authenticate returns the principal of the key
+	Authenticate(ctx context.Context, key string) (p *Principal, err error)
+}
+
+var (
+	// ErrAuthKeyInvalid is returned when the api key does not exist or is revoked
+	ErrAuthKeyInvalid = errors.New("api key invalid")
+	// ErrAuthRoleInvalid is returned when a role is not one of the roles
+	ErrAuthRoleInvalid = errors.New("role invalid")
+)
+
+// HashKey returns the hash of the key stored in place of it (hex sha256, enough for random keys)
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewAuthenticatorAPIKey is a constructor for an authenticator of the api keys of st
+func NewAuthenticatorAPIKey(st storage.StorageAPIKey) *AuthenticatorAPIKey {
+	return &AuthenticatorAPIKey{st: st}
+}
+
+// AuthenticatorAPIKey is a struct that authenticates the api keys of a storage, by their hash
+type AuthenticatorAPIKey struct {
+	st storage.StorageAPIKey
+}
+
+// Authenticate returns the principal of the key, or ErrAuthKeyInvalid if it is unknown or revoked
+func (a *AuthenticatorAPIKey) Authenticate(ctx context.Context, key string) (p *Principal, err error) {
+	// api key
+	k, err := a.st.ReadByHash(ctx, HashKey(key))
+	if err != nil {
+		if errors.Is(err, storage.ErrStorageAPIKeyNotFound) {
+			err = ErrAuthKeyInvalid
+		}
+		return
+	}
+	if !k.RevokedAt.IsZero() {
+		err = fmt.Errorf("%w. revoked", ErrAuthKeyInvalid)
+		return
+	}
+	role, err := ParseRole(k.Role)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrAuthKeyInvalid, err)
+		return
+	}
+
+	p = &Principal{KeyId: k.Id, Name: k.Name, Role: role}
+	return
+}
+
+// Issue creates an api key with the name and role in st and returns it (only its hash is stored,
+// so the key can not be read again)
+func Issue(ctx context.Context, st storage.StorageAPIKey, name string, role Role) (key string, k *storage.APIKey, err error) {
+	if _, ok := roleLevels[role]; !ok {
+		err = fmt.Errorf("%w. %q", ErrAuthRoleInvalid, role)
+		return
+	}
+
+	// key (256 random bits)
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+	key = base64.RawURLEncoding.EncodeToString(b)
+
+	// store
+	k = &storage.APIKey{Name: name, Hash: HashKey(key), Role: string(role), CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	if err = st.Create(ctx, k); err != nil {
+		key, k = "", nil
+		return
+	}
+
+	return
+}
+
+// principalKey is the context key of the principal
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx with the principal
+func ContextWithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal of ctx, or nil if the request was not authenticated
+func PrincipalFrom(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"app/internal/apikeys/storage"
+	"app/internal/sqlitedb"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Role.Allows method
+func TestRole_Allows(t *testing.T) {
+	type input struct { role Role; required Role }
+	type output struct { allowed bool }
+	type testCase struct {
+		name string
+		input input
+		output output
+	}
+
+	cases := []testCase{
+		{name: "reader reads", input: input{role: RoleReader, required: RoleReader}, output: output{allowed: true}},
+		{name: "reader does not sell", input: input{role: RoleReader, required: RoleCashier}, output: output{allowed: false}},
+		{name: "cashier reads", input: input{role: RoleCashier, required: RoleReader}, output: output{allowed: true}},
+		{name: "cashier does not administer", input: input{role: RoleCashier, required: RoleAdmin}, output: output{allowed: false}},
+		{name: "admin administers", input: input{role: RoleAdmin, required: RoleAdmin}, output: output{allowed: true}},
+		{name: "unknown role", input: input{role: Role("root"), required: RoleReader}, output: output{allowed: false}},
+	}
+
+	// run tests
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			allowed := c.input.role.Allows(c.input.required)
+
+			// assert
+			require.Equal(t, c.output.allowed, allowed)
+		})
+	}
+}
+
+// Tests for AuthenticatorAPIKey.Authenticate method
+func TestAuthenticatorAPIKey_Authenticate(t *testing.T) {
+	// arrange
+	db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+	require.NoError(t, err)
+	defer db.Close()
+	st := storage.NewStorageAPIKeySQLite(db)
+	au := NewAuthenticatorAPIKey(st)
+	key, k, err := Issue(context.Background(), st, "front desk", RoleCashier)
+	require.NoError(t, err)
+
+	t.Run("valid key", func(t *testing.T) {
+		// act
+		p, err := au.Authenticate(context.Background(), key)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, &Principal{KeyId: k.Id, Name: "front desk", Role: RoleCashier}, p)
+		require.NotEqual(t, key, k.Hash)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		// act
+		p, err := au.Authenticate(context.Background(), "not-a-key")
+
+		// assert
+		require.Nil(t, p)
+		require.ErrorIs(t, err, ErrAuthKeyInvalid)
+	})
+
+	t.Run("revoked key", func(t *testing.T) {
+		// arrange
+		require.NoError(t, st.Revoke(context.Background(), k.Id, time.Now().UTC()))
+
+		// act
+		p, err := au.Authenticate(context.Background(), key)
+
+		// assert
+		require.Nil(t, p)
+		require.ErrorIs(t, err, ErrAuthKeyInvalid)
+	})
+}
+
+// Tests for Issue function
+func TestIssue(t *testing.T) {
+	t.Run("invalid role", func(t *testing.T) {
+		// act
+		key, k, err := Issue(context.Background(), nil, "front desk", Role("root"))
+
+		// assert
+		require.Empty(t, key)
+		require.Nil(t, k)
+		require.ErrorIs(t, err, ErrAuthRoleInvalid)
+	})
+}
@@ -0,0 +1,106 @@
+package checkout
+
+import (
+	invoicesStorage "app/internal/invoices/storage"
+	productsStorage "app/internal/products/storage"
+	salesStorage "app/internal/sales/storage"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Item is a struct that represents a line item of an order
+type Item struct {
+	ProductId int
+	Quantity  int
+}
+
+// Order is a struct that represents the invoice of a customer with its line items
+type Order struct {
+	CustomerId int
+	// Datetime is the datetime of the invoice (the current time if zero)
+	Datetime time.Time
+	Items    []Item
+}
+
+// Receipt is a struct that represents the invoice and the sales created by a checkout
+type Receipt struct {
+	Invoice *invoicesStorage.Invoice
+	Sales   []*salesStorage.Sale
+}
+
+// Checkout is an interface that represents the checkout use case
+type Checkout interface {
+	// Checkout creates the invoice of the order and a sale for each item, with the total computed from the
+	// current product prices. Either everything is created or nothing is. The errors of the storages are returned as is:
+	// - a customer that does not exist returns invoicesStorage.ErrStorageInvoiceRelation
+	// - a product that does not exist returns salesStorage.ErrStorageSaleRelationProduct
+	Checkout(ctx context.Context, o *Order) (r *Receipt, err error)
+}
+
+var (
+	// ErrCheckoutInvalid is returned when the order has no items or an item has a non-positive quantity
+	ErrCheckoutInvalid = errors.New("checkout order invalid")
+)
+
+// checkout creates the invoice of the order and its sales through the storages (that must share a transaction)
+func checkout(ctx context.Context, o *Order, stProduct productsStorage.StorageProduct, stInvoice invoicesStorage.StorageInvoice, stSale salesStorage.StorageSale) (r *Receipt, err error) {
+	// prices
+	var total float64
+	for _, it := range o.Items {
+		var p *productsStorage.Product
+		p, err = stProduct.ReadById(ctx, it.ProductId)
+		if err != nil {
+			if errors.Is(err, productsStorage.ErrStorageProductNotFound) {
+				err = fmt.Errorf("%w. product %d", salesStorage.ErrStorageSaleRelationProduct, it.ProductId)
+			}
+			return
+		}
+		total += float64(it.Quantity) * p.Price
+	}
+
+	// invoice
+	inv := &invoicesStorage.Invoice{
+		Datetime:   o.Datetime,
+		Total:      math.Round(total*100) / 100,
+		CustomerId: o.CustomerId,
+	}
+	if inv.Datetime.IsZero() {
+		inv.Datetime = time.Now().UTC().Truncate(time.Second)
+	}
+	err = stInvoice.Create(ctx, inv)
+	if err != nil {
+		return
+	}
+
+	// sales
+	ss := make([]*salesStorage.Sale, 0, len(o.Items))
+	for _, it := range o.Items {
+		sa := &salesStorage.Sale{Quantity: it.Quantity, ProductId: it.ProductId, InvoiceId: inv.Id}
+		err = stSale.Create(ctx, sa)
+		if err != nil {
+			return
+		}
+		ss = append(ss, sa)
+	}
+
+	r = &Receipt{Invoice: inv, Sales: ss}
+	return
+}
+
+// validate checks the items of the order
+func validate(o *Order) (err error) {
+	if len(o.Items) == 0 {
+		err = ErrCheckoutInvalid
+		return
+	}
+	for _, it := range o.Items {
+		if it.Quantity <= 0 {
+			err = ErrCheckoutInvalid
+			return
+		}
+	}
+	return
+}
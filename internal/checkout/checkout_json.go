@@ -0,0 +1,41 @@
+package checkout
+
+import (
+	invoicesStorage "app/internal/invoices/storage"
+	"app/internal/jsondb"
+	"app/internal/memdb"
+	productsStorage "app/internal/products/storage"
+	salesStorage "app/internal/sales/storage"
+	"context"
+	"path/filepath"
+)
+
+// NewCheckoutJSON returns a new instance of CheckoutJSON for the json files of the directory dir
+func NewCheckoutJSON(dir string) *CheckoutJSON {
+	return &CheckoutJSON{db: jsondb.New(jsondb.FilesFor(jsondb.TableInvoices, filepath.Join(dir, jsondb.FileInvoices)))}
+}
+
+// CheckoutJSON is a struct that represents the checkout use case in json files for Checkout interface
+type CheckoutJSON struct {
+	db *jsondb.DB
+}
+
+// Checkout creates the invoice of the order and its sales in the data loaded from the files,
+// which are only saved if everything was created
+func (c *CheckoutJSON) Checkout(ctx context.Context, o *Order) (r *Receipt, err error) {
+	err = validate(o)
+	if err != nil {
+		return
+	}
+
+	err = c.db.Update(func(mdb *memdb.DB) (err error) {
+		r, err = checkout(ctx, o, productsStorage.NewStorageProductMap(mdb), invoicesStorage.NewStorageInvoiceMap(mdb), salesStorage.NewStorageSaleMap(mdb))
+		return
+	}, jsondb.TableInvoices, jsondb.TableSales)
+	if err != nil {
+		r = nil
+		return
+	}
+
+	return
+}
@@ -0,0 +1,47 @@
+package checkout
+
+import (
+	invoicesStorage "app/internal/invoices/storage"
+	productsStorage "app/internal/products/storage"
+	salesStorage "app/internal/sales/storage"
+	"app/internal/sqltx"
+	"context"
+	"database/sql"
+)
+
+// NewCheckoutMySQL returns a new instance of CheckoutMySQL
+func NewCheckoutMySQL(db *sql.DB) *CheckoutMySQL {
+	return &CheckoutMySQL{
+		runner:    sqltx.NewRunner(db),
+		stProduct: productsStorage.NewStorageProductMySQL(db),
+		stInvoice: invoicesStorage.NewStorageInvoiceMySQL(db),
+		stSale:    salesStorage.NewStorageSaleMySQL(db),
+	}
+}
+
+// CheckoutMySQL is a struct that represents the checkout use case in MySQL for Checkout interface
+type CheckoutMySQL struct {
+	runner    *sqltx.Runner
+	stProduct *productsStorage.StorageProductMySQL
+	stInvoice *invoicesStorage.StorageInvoiceMySQL
+	stSale    *salesStorage.StorageSaleMySQL
+}
+
+// Checkout creates the invoice of the order and its sales in a transaction
+func (c *CheckoutMySQL) Checkout(ctx context.Context, o *Order) (r *Receipt, err error) {
+	err = validate(o)
+	if err != nil {
+		return
+	}
+
+	err = c.runner.WithTx(ctx, func(tx *sql.Tx) (err error) {
+		r, err = checkout(ctx, o, c.stProduct.InTx(tx), c.stInvoice.InTx(tx), c.stSale.InTx(tx))
+		return
+	})
+	if err != nil {
+		r = nil
+		return
+	}
+
+	return
+}
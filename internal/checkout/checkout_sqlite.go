@@ -0,0 +1,47 @@
+package checkout
+
+import (
+	invoicesStorage "app/internal/invoices/storage"
+	productsStorage "app/internal/products/storage"
+	salesStorage "app/internal/sales/storage"
+	"app/internal/sqltx"
+	"context"
+	"database/sql"
+)
+
+// NewCheckoutSQLite returns a new instance of CheckoutSQLite
+func NewCheckoutSQLite(db *sql.DB) *CheckoutSQLite {
+	return &CheckoutSQLite{
+		runner:    sqltx.NewRunner(db),
+		stProduct: productsStorage.NewStorageProductSQLite(db),
+		stInvoice: invoicesStorage.NewStorageInvoiceSQLite(db),
+		stSale:    salesStorage.NewStorageSaleSQLite(db),
+	}
+}
+
+// CheckoutSQLite is a struct that represents the checkout use case in SQLite for Checkout interface
+type CheckoutSQLite struct {
+	runner    *sqltx.Runner
+	stProduct *productsStorage.StorageProductSQLite
+	stInvoice *invoicesStorage.StorageInvoiceSQLite
+	stSale    *salesStorage.StorageSaleSQLite
+}
+
+// Checkout creates the invoice of the order and its sales in a transaction
+func (c *CheckoutSQLite) Checkout(ctx context.Context, o *Order) (r *Receipt, err error) {
+	err = validate(o)
+	if err != nil {
+		return
+	}
+
+	err = c.runner.WithTx(ctx, func(tx *sql.Tx) (err error) {
+		r, err = checkout(ctx, o, c.stProduct.InTx(tx), c.stInvoice.InTx(tx), c.stSale.InTx(tx))
+		return
+	})
+	if err != nil {
+		r = nil
+		return
+	}
+
+	return
+}
@@ -0,0 +1,150 @@
+package checkout
+
+import (
+	invoicesStorage "app/internal/invoices/storage"
+	"app/internal/jsondb"
+	salesStorage "app/internal/sales/storage"
+	"app/internal/sqlitedb"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteDB returns an in-memory SQLite database with a customer and two products
+func newSQLiteDB(t *testing.T) *sql.DB {
+	db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec("INSERT INTO customers (id) VALUES (1); INSERT INTO products (id, price) VALUES (1, 12.89), (2, 46.05)")
+	require.NoError(t, err)
+	return db
+}
+
+// count returns the number of rows of the table
+func count(t *testing.T, db *sql.DB, table string) (n int) {
+	err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n)
+	require.NoError(t, err)
+	return
+}
+
+// Tests for CheckoutSQLite
+func TestCheckoutSQLite_Checkout(t *testing.T) {
+	t.Run("invoice and sales with the total of the current prices", func(t *testing.T) {
+		// arrange
+		db := newSQLiteDB(t)
+		dt := time.Date(2022, 3, 15, 10, 0, 0, 0, time.UTC)
+
+		// act
+		r, err := NewCheckoutSQLite(db).Checkout(context.Background(), &Order{CustomerId: 1, Datetime: dt, Items: []Item{{ProductId: 1, Quantity: 3}, {ProductId: 2, Quantity: 1}}})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, &invoicesStorage.Invoice{Id: 1, Datetime: dt, Total: 84.72, CustomerId: 1}, r.Invoice)
+		require.Equal(t, []*salesStorage.Sale{{Id: 1, Quantity: 3, ProductId: 1, InvoiceId: 1}, {Id: 2, Quantity: 1, ProductId: 2, InvoiceId: 1}}, r.Sales)
+		require.Equal(t, 1, count(t, db, "invoices"))
+		require.Equal(t, 2, count(t, db, "sales"))
+	})
+
+	t.Run("invalid order", func(t *testing.T) {
+		// arrange
+		db := newSQLiteDB(t)
+
+		// act
+		_, errEmpty := NewCheckoutSQLite(db).Checkout(context.Background(), &Order{CustomerId: 1})
+		_, errQuantity := NewCheckoutSQLite(db).Checkout(context.Background(), &Order{CustomerId: 1, Items: []Item{{ProductId: 1, Quantity: 0}}})
+
+		// assert
+		require.ErrorIs(t, errEmpty, ErrCheckoutInvalid)
+		require.ErrorIs(t, errQuantity, ErrCheckoutInvalid)
+	})
+
+	t.Run("customer not found", func(t *testing.T) {
+		// arrange
+		db := newSQLiteDB(t)
+
+		// act
+		r, err := NewCheckoutSQLite(db).Checkout(context.Background(), &Order{CustomerId: 2, Items: []Item{{ProductId: 1, Quantity: 1}}})
+
+		// assert
+		require.Nil(t, r)
+		require.ErrorIs(t, err, invoicesStorage.ErrStorageInvoiceRelation)
+		require.Equal(t, 0, count(t, db, "invoices"))
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		// arrange
+		db := newSQLiteDB(t)
+
+		// act
+		r, err := NewCheckoutSQLite(db).Checkout(context.Background(), &Order{CustomerId: 1, Items: []Item{{ProductId: 1, Quantity: 1}, {ProductId: 3, Quantity: 1}}})
+
+		// assert
+		require.Nil(t, r)
+		require.ErrorIs(t, err, salesStorage.ErrStorageSaleRelationProduct)
+		require.Equal(t, 0, count(t, db, "invoices"))
+		require.Equal(t, 0, count(t, db, "sales"))
+	})
+
+	t.Run("failed sale rolls back the invoice and the previous sales", func(t *testing.T) {
+		// arrange
+		db := newSQLiteDB(t)
+		_, err := db.Exec("CREATE TRIGGER fail_sale BEFORE INSERT ON sales WHEN NEW.product_id = 2 BEGIN SELECT RAISE(ABORT, 'sale failed'); END")
+		require.NoError(t, err)
+
+		// act
+		r, err := NewCheckoutSQLite(db).Checkout(context.Background(), &Order{CustomerId: 1, Items: []Item{{ProductId: 1, Quantity: 1}, {ProductId: 2, Quantity: 1}}})
+
+		// assert
+		require.Nil(t, r)
+		require.ErrorIs(t, err, salesStorage.ErrStorageSaleInternal)
+		require.Equal(t, 0, count(t, db, "invoices"))
+		require.Equal(t, 0, count(t, db, "sales"))
+	})
+}
+
+// newJSONDir returns a directory with the json files of a customer and two products
+func newJSONDir(t *testing.T) (dir string) {
+	dir = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, jsondb.FileCustomers), []byte(`[{"id":1,"last_name":"","first_name":"","condition":false}]`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, jsondb.FileProducts), []byte(`[{"id":1,"description":"","price":12.89},{"id":2,"description":"","price":46.05}]`), 0o600))
+	return
+}
+
+// Tests for CheckoutJSON
+func TestCheckoutJSON_Checkout(t *testing.T) {
+	t.Run("invoice and sales saved in the files", func(t *testing.T) {
+		// arrange
+		dir := newJSONDir(t)
+		dt := time.Date(2022, 3, 15, 10, 0, 0, 0, time.UTC)
+
+		// act
+		r, err := NewCheckoutJSON(dir).Checkout(context.Background(), &Order{CustomerId: 1, Datetime: dt, Items: []Item{{ProductId: 1, Quantity: 3}, {ProductId: 2, Quantity: 1}}})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, &invoicesStorage.Invoice{Id: 1, Datetime: dt, Total: 84.72, CustomerId: 1}, r.Invoice)
+		var sales []*jsondb.SaleJSON
+		require.NoError(t, jsondb.ReadFile(filepath.Join(dir, jsondb.FileSales), &sales))
+		require.Equal(t, []*jsondb.SaleJSON{{Id: 1, ProductId: 1, InvoiceId: 1, Quantity: 3}, {Id: 2, ProductId: 2, InvoiceId: 1, Quantity: 1}}, sales)
+	})
+
+	t.Run("product not found saves nothing", func(t *testing.T) {
+		// arrange
+		dir := newJSONDir(t)
+
+		// act
+		r, err := NewCheckoutJSON(dir).Checkout(context.Background(), &Order{CustomerId: 1, Items: []Item{{ProductId: 1, Quantity: 1}, {ProductId: 3, Quantity: 1}}})
+
+		// assert
+		require.Nil(t, r)
+		require.ErrorIs(t, err, salesStorage.ErrStorageSaleRelationProduct)
+		require.NoFileExists(t, filepath.Join(dir, jsondb.FileInvoices))
+		require.NoFileExists(t, filepath.Join(dir, jsondb.FileSales))
+	})
+}
@@ -0,0 +1,346 @@
+package config
+
+import (
+	"app/internal/sqlitedb"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration of the server
+type Config struct {
+	// Db is the database configuration
+	Db ConfigDb
+	// Server is the http server configuration
+	Server ConfigServer
+}
+
+// ConfigDb is the database configuration: the driver and its connection (User to ParseTime for mysql, Path for sqlite and json)
+type ConfigDb struct {
+	// Driver is the database of the storages (DriverMySQL, DriverSQLite or DriverJSON)
+	Driver string
+	// Path is the sqlite database file, or the directory of the json files
+	Path      string
+	User      string
+	Password  string
+	Net       string
+	Addr      string
+	DBName    string
+	ParseTime bool
+	// QueryTimeout is the maximum duration of the database operations of a request (0 is no limit)
+	QueryTimeout time.Duration
+}
+
+// ConfigServer is the http server configuration
+type ConfigServer struct {
+	Addr         string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout is the maximum duration to drain the in-flight requests on shutdown
+	ShutdownTimeout time.Duration
+}
+
+// Drivers of the database
+const (
+	DriverMySQL  = "mysql"
+	DriverSQLite = "sqlite"
+	// DriverJSON stores the tables in the json files of docs/db/json, without a sql database
+	DriverJSON = "json"
+)
+
+// MySQL returns the mysql driver configuration
+func (c *ConfigDb) MySQL() (cfg *mysql.Config) {
+	cfg = mysql.NewConfig()
+	cfg.User = c.User
+	cfg.Passwd = c.Password
+	cfg.Net = c.Net
+	cfg.Addr = c.Addr
+	cfg.DBName = c.DBName
+	cfg.ParseTime = c.ParseTime
+	return
+}
+
+// Open opens the sql database of the driver (the sqlite one is created with its tables if it does not exist).
+// The json driver has no sql database: its storages open the files of Path
+func (c *ConfigDb) Open() (db *sql.DB, err error) {
+	switch c.Driver {
+	case DriverMySQL:
+		db, err = sql.Open("mysql", c.MySQL().FormatDSN())
+	case DriverSQLite:
+		db, err = sqlitedb.Open(c.Path)
+	case DriverJSON:
+		err = fmt.Errorf("%w. the %s driver has no sql database", ErrConfigInvalid, c.Driver)
+	default:
+		err = fmt.Errorf("%w. unknown db driver %q", ErrConfigInvalid, c.Driver)
+	}
+	return
+}
+
+// Default returns the default configuration
+func Default() (c *Config) {
+	c = &Config{
+		Db: ConfigDb{
+			Driver:       DriverMySQL,
+			User:         "root",
+			Password:     "",
+			Net:          "tcp",
+			Addr:         "localhost:3306",
+			DBName:       "storage_desafio_db",
+			ParseTime:    true,
+			QueryTimeout: 5 * time.Second,
+		},
+		Server: ConfigServer{
+			Addr:            "127.0.0.1:8080",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+		},
+	}
+	return
+}
+
+// Environment variables read by Load
+const (
+	EnvConfigFile            = "CONFIG_FILE"
+	EnvDbDriver              = "DB_DRIVER"
+	EnvDbPath                = "DB_PATH"
+	EnvDbUser                = "DB_USER"
+	EnvDbPassword            = "DB_PASSWORD"
+	EnvDbNet                 = "DB_NET"
+	EnvDbAddr                = "DB_ADDR"
+	EnvDbName                = "DB_NAME"
+	EnvDbParseTime           = "DB_PARSE_TIME"
+	EnvDbQueryTimeout        = "DB_QUERY_TIMEOUT"
+	EnvServerAddr            = "SERVER_ADDR"
+	EnvServerReadTimeout     = "SERVER_READ_TIMEOUT"
+	EnvServerWriteTimeout    = "SERVER_WRITE_TIMEOUT"
+	EnvServerIdleTimeout     = "SERVER_IDLE_TIMEOUT"
+	EnvServerShutdownTimeout = "SERVER_SHUTDOWN_TIMEOUT"
+)
+
+var (
+	// ErrConfigInvalid is returned when the configuration is invalid
+	ErrConfigInvalid = errors.New("config invalid")
+	// ErrConfigFile is returned when the configuration file can not be read
+	ErrConfigFile = errors.New("config file invalid")
+)
+
+// LookupEnvFunc is a function that looks up an environment variable (such as os.LookupEnv)
+type LookupEnvFunc func(key string) (value string, ok bool)
+
+// Load builds the configuration from the defaults, the optional file set in CONFIG_FILE
+// and the environment variables, in that order of precedence (the last one wins)
+func Load(lookupEnv LookupEnvFunc) (c *Config, err error) {
+	c = Default()
+
+	// file
+	if path, ok := lookupEnv(EnvConfigFile); ok && path != "" {
+		err = c.loadFile(path)
+		if err != nil {
+			c = nil
+			return
+		}
+	}
+
+	// env
+	var problems []string
+	c.loadEnv(lookupEnv, &problems)
+
+	// validate
+	c.validate(&problems)
+	if len(problems) > 0 {
+		c = nil
+		err = fmt.Errorf("%w. %s", ErrConfigInvalid, strings.Join(problems, "; "))
+		return
+	}
+
+	return
+}
+
+// configFile is the representation of the configuration file (json or yaml)
+type configFile struct {
+	Db *struct {
+		Driver       *string `json:"driver" yaml:"driver"`
+		Path         *string `json:"path" yaml:"path"`
+		User         *string `json:"user" yaml:"user"`
+		Password     *string `json:"password" yaml:"password"`
+		Net          *string `json:"net" yaml:"net"`
+		Addr         *string `json:"addr" yaml:"addr"`
+		DBName       *string `json:"db_name" yaml:"db_name"`
+		ParseTime    *bool   `json:"parse_time" yaml:"parse_time"`
+		QueryTimeout *string `json:"query_timeout" yaml:"query_timeout"`
+	} `json:"db" yaml:"db"`
+	Server *struct {
+		Addr            *string `json:"addr" yaml:"addr"`
+		ReadTimeout     *string `json:"read_timeout" yaml:"read_timeout"`
+		WriteTimeout    *string `json:"write_timeout" yaml:"write_timeout"`
+		IdleTimeout     *string `json:"idle_timeout" yaml:"idle_timeout"`
+		ShutdownTimeout *string `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	} `json:"server" yaml:"server"`
+}
+
+// loadFile overrides the configuration with the values of a json or yaml file
+func (c *Config) loadFile(path string) (err error) {
+	// read file
+	var b []byte
+	b, err = os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrConfigFile, err)
+		return
+	}
+
+	// decode by extension
+	var f configFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&f)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		err = dec.Decode(&f)
+	default:
+		err = fmt.Errorf("unsupported extension %q (expected .json, .yaml or .yml)", filepath.Ext(path))
+	}
+	if err != nil {
+		err = fmt.Errorf("%w. %s: %v", ErrConfigFile, path, err)
+		return
+	}
+
+	// override values
+	var problems []string
+	if f.Db != nil {
+		setString(&c.Db.Driver, f.Db.Driver)
+		setString(&c.Db.Path, f.Db.Path)
+		setString(&c.Db.User, f.Db.User)
+		setString(&c.Db.Password, f.Db.Password)
+		setString(&c.Db.Net, f.Db.Net)
+		setString(&c.Db.Addr, f.Db.Addr)
+		setString(&c.Db.DBName, f.Db.DBName)
+		if f.Db.ParseTime != nil {
+			c.Db.ParseTime = *f.Db.ParseTime
+		}
+		setDuration(&c.Db.QueryTimeout, f.Db.QueryTimeout, "db.query_timeout", &problems)
+	}
+	if f.Server != nil {
+		setString(&c.Server.Addr, f.Server.Addr)
+		setDuration(&c.Server.ReadTimeout, f.Server.ReadTimeout, "server.read_timeout", &problems)
+		setDuration(&c.Server.WriteTimeout, f.Server.WriteTimeout, "server.write_timeout", &problems)
+		setDuration(&c.Server.IdleTimeout, f.Server.IdleTimeout, "server.idle_timeout", &problems)
+		setDuration(&c.Server.ShutdownTimeout, f.Server.ShutdownTimeout, "server.shutdown_timeout", &problems)
+	}
+	if len(problems) > 0 {
+		err = fmt.Errorf("%w. %s: %s", ErrConfigFile, path, strings.Join(problems, "; "))
+		return
+	}
+
+	return
+}
+
+// loadEnv overrides the configuration with the environment variables that are set
+func (c *Config) loadEnv(lookupEnv LookupEnvFunc, problems *[]string) {
+	env := func(key string) *string {
+		if v, ok := lookupEnv(key); ok {
+			return &v
+		}
+		return nil
+	}
+
+	setString(&c.Db.Driver, env(EnvDbDriver))
+	setString(&c.Db.Path, env(EnvDbPath))
+	setString(&c.Db.User, env(EnvDbUser))
+	setString(&c.Db.Password, env(EnvDbPassword))
+	setString(&c.Db.Net, env(EnvDbNet))
+	setString(&c.Db.Addr, env(EnvDbAddr))
+	setString(&c.Db.DBName, env(EnvDbName))
+	if v := env(EnvDbParseTime); v != nil {
+		b, err := strconv.ParseBool(*v)
+		if err != nil {
+			*problems = append(*problems, fmt.Sprintf("%s: invalid boolean %q", EnvDbParseTime, *v))
+		} else {
+			c.Db.ParseTime = b
+		}
+	}
+	setDuration(&c.Db.QueryTimeout, env(EnvDbQueryTimeout), EnvDbQueryTimeout, problems)
+	setString(&c.Server.Addr, env(EnvServerAddr))
+	setDuration(&c.Server.ReadTimeout, env(EnvServerReadTimeout), EnvServerReadTimeout, problems)
+	setDuration(&c.Server.WriteTimeout, env(EnvServerWriteTimeout), EnvServerWriteTimeout, problems)
+	setDuration(&c.Server.IdleTimeout, env(EnvServerIdleTimeout), EnvServerIdleTimeout, problems)
+	setDuration(&c.Server.ShutdownTimeout, env(EnvServerShutdownTimeout), EnvServerShutdownTimeout, problems)
+}
+
+// validate checks the configuration values
+func (c *Config) validate(problems *[]string) {
+	switch c.Db.Driver {
+	case DriverMySQL:
+		if c.Db.User == "" {
+			*problems = append(*problems, "db user is required")
+		}
+		if c.Db.Net != "tcp" && c.Db.Net != "unix" {
+			*problems = append(*problems, fmt.Sprintf("db net must be tcp or unix, got %q", c.Db.Net))
+		}
+		if c.Db.Addr == "" {
+			*problems = append(*problems, "db addr is required")
+		}
+		if c.Db.DBName == "" {
+			*problems = append(*problems, "db name is required")
+		}
+	case DriverSQLite, DriverJSON:
+		if c.Db.Path == "" {
+			*problems = append(*problems, fmt.Sprintf("db path is required with the %s driver", c.Db.Driver))
+		}
+	default:
+		*problems = append(*problems, fmt.Sprintf("db driver must be %s, %s or %s, got %q", DriverMySQL, DriverSQLite, DriverJSON, c.Db.Driver))
+	}
+	if c.Db.QueryTimeout < 0 {
+		*problems = append(*problems, "db query timeout must not be negative")
+	}
+	if c.Server.Addr == "" {
+		*problems = append(*problems, "server addr is required")
+	}
+	if c.Server.ReadTimeout < 0 {
+		*problems = append(*problems, "server read timeout must not be negative")
+	}
+	if c.Server.WriteTimeout < 0 {
+		*problems = append(*problems, "server write timeout must not be negative")
+	}
+	if c.Server.IdleTimeout < 0 {
+		*problems = append(*problems, "server idle timeout must not be negative")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		*problems = append(*problems, "server shutdown timeout must be positive")
+	}
+}
+
+// setString sets dst to the value of src if src is not nil
+func setString(dst *string, src *string) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// setDuration parses src and sets dst if src is not nil, recording a problem if it can not be parsed
+func setDuration(dst *time.Duration, src *string, name string, problems *[]string) {
+	if src == nil {
+		return
+	}
+	d, err := time.ParseDuration(*src)
+	if err != nil {
+		*problems = append(*problems, fmt.Sprintf("%s: invalid duration %q", name, *src))
+		return
+	}
+	*dst = d
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lookupEnvMap returns a LookupEnvFunc backed by a map
+func lookupEnvMap(m map[string]string) LookupEnvFunc {
+	return func(key string) (value string, ok bool) {
+		value, ok = m[key]
+		return
+	}
+}
+
+// Tests for Load function
+func TestLoad(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		cfg, err := Load(lookupEnvMap(nil))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, Default(), cfg)
+	})
+
+	t.Run("env overrides defaults", func(t *testing.T) {
+		// arrange
+		env := map[string]string{
+			EnvDbUser:                "app",
+			EnvDbPassword:            "secret",
+			EnvDbAddr:                "db:3306",
+			EnvDbParseTime:           "false",
+			EnvDbQueryTimeout:        "500ms",
+			EnvServerAddr:            ":9090",
+			EnvServerReadTimeout:     "3s",
+			EnvServerShutdownTimeout: "30s",
+		}
+
+		// act
+		cfg, err := Load(lookupEnvMap(env))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "app", cfg.Db.User)
+		require.Equal(t, "secret", cfg.Db.Password)
+		require.Equal(t, "db:3306", cfg.Db.Addr)
+		require.False(t, cfg.Db.ParseTime)
+		require.Equal(t, 500*time.Millisecond, cfg.Db.QueryTimeout)
+		require.Equal(t, ":9090", cfg.Server.Addr)
+		require.Equal(t, 3*time.Second, cfg.Server.ReadTimeout)
+		require.Equal(t, Default().Server.WriteTimeout, cfg.Server.WriteTimeout)
+		require.Equal(t, 30*time.Second, cfg.Server.ShutdownTimeout)
+	})
+
+	t.Run("file overrides defaults and env overrides file", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		content := "db:\n  user: file\n  db_name: other_db\nserver:\n  addr: \":7070\"\n  idle_timeout: 2m\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		env := map[string]string{
+			EnvConfigFile: path,
+			EnvDbUser:     "env",
+		}
+
+		// act
+		cfg, err := Load(lookupEnvMap(env))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "env", cfg.Db.User)
+		require.Equal(t, "other_db", cfg.Db.DBName)
+		require.Equal(t, ":7070", cfg.Server.Addr)
+		require.Equal(t, 2*time.Minute, cfg.Server.IdleTimeout)
+	})
+
+	t.Run("json file", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "config.json")
+		content := `{"db": {"addr": "mysql:3306"}, "server": {"write_timeout": "15s"}}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		// act
+		cfg, err := Load(lookupEnvMap(map[string]string{EnvConfigFile: path}))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "mysql:3306", cfg.Db.Addr)
+		require.Equal(t, 15*time.Second, cfg.Server.WriteTimeout)
+	})
+
+	t.Run("invalid env values", func(t *testing.T) {
+		// arrange
+		env := map[string]string{
+			EnvDbNet:                 "udp",
+			EnvDbParseTime:           "maybe",
+			EnvDbQueryTimeout:        "-1s",
+			EnvServerWriteTimeout:    "ten seconds",
+			EnvServerShutdownTimeout: "0s",
+		}
+
+		// act
+		cfg, err := Load(lookupEnvMap(env))
+
+		// assert
+		require.Nil(t, cfg)
+		require.ErrorIs(t, err, ErrConfigInvalid)
+		require.ErrorContains(t, err, `DB_PARSE_TIME: invalid boolean "maybe"`)
+		require.ErrorContains(t, err, `SERVER_WRITE_TIMEOUT: invalid duration "ten seconds"`)
+		require.ErrorContains(t, err, `db net must be tcp or unix, got "udp"`)
+		require.ErrorContains(t, err, "db query timeout must not be negative")
+		require.ErrorContains(t, err, "server shutdown timeout must be positive")
+	})
+
+	t.Run("sqlite driver", func(t *testing.T) {
+		// arrange (the mysql values are not required)
+		env := map[string]string{
+			EnvDbDriver: DriverSQLite,
+			EnvDbPath:   "data/app.db",
+			EnvDbUser:   "",
+			EnvDbNet:    "udp",
+		}
+
+		// act
+		cfg, err := Load(lookupEnvMap(env))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, DriverSQLite, cfg.Db.Driver)
+		require.Equal(t, "data/app.db", cfg.Db.Path)
+	})
+
+	t.Run("invalid driver settings", func(t *testing.T) {
+		// arrange
+		pathSQLite := filepath.Join(t.TempDir(), "sqlite.yaml")
+		require.NoError(t, os.WriteFile(pathSQLite, []byte("db:\n  driver: sqlite\n"), 0o600))
+
+		// act
+		cfgUnknown, errUnknown := Load(lookupEnvMap(map[string]string{EnvDbDriver: "postgres"}))
+		cfgSQLite, errSQLite := Load(lookupEnvMap(map[string]string{EnvConfigFile: pathSQLite}))
+
+		// assert
+		require.Nil(t, cfgUnknown)
+		require.ErrorIs(t, errUnknown, ErrConfigInvalid)
+		require.ErrorContains(t, errUnknown, `db driver must be mysql, sqlite or json, got "postgres"`)
+		require.Nil(t, cfgSQLite)
+		require.ErrorContains(t, errSQLite, "db path is required with the sqlite driver")
+	})
+
+	t.Run("unknown field in file", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"db": {"host": "x"}}`), 0o600))
+
+		// act
+		cfg, err := Load(lookupEnvMap(map[string]string{EnvConfigFile: path}))
+
+		// assert
+		require.Nil(t, cfg)
+		require.ErrorIs(t, err, ErrConfigFile)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "missing.yaml")
+
+		// act
+		cfg, err := Load(lookupEnvMap(map[string]string{EnvConfigFile: path}))
+
+		// assert
+		require.Nil(t, cfg)
+		require.ErrorIs(t, err, ErrConfigFile)
+	})
+}
+
+// Tests for ConfigDb.Open method
+func TestConfigDb_Open(t *testing.T) {
+	t.Run("sqlite creates the tables", func(t *testing.T) {
+		// arrange
+		c := &ConfigDb{Driver: DriverSQLite, Path: filepath.Join(t.TempDir(), "app.db")}
+
+		// act
+		db, err := c.Open()
+
+		// assert
+		require.NoError(t, err)
+		defer db.Close()
+		var n int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sales").Scan(&n))
+		require.Zero(t, n)
+	})
+
+	t.Run("json and unknown drivers", func(t *testing.T) {
+		// arrange
+		cJSON := &ConfigDb{Driver: DriverJSON, Path: t.TempDir()}
+		cUnknown := &ConfigDb{Driver: "postgres"}
+
+		// act
+		dbJSON, errJSON := cJSON.Open()
+		dbUnknown, errUnknown := cUnknown.Open()
+
+		// assert
+		require.Nil(t, dbJSON)
+		require.ErrorIs(t, errJSON, ErrConfigInvalid)
+		require.Nil(t, dbUnknown)
+		require.ErrorIs(t, errUnknown, ErrConfigInvalid)
+	})
+}
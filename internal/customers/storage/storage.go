@@ -1,6 +1,10 @@
 package storage
 
-import "errors"
+import (
+	"app/internal/query"
+	"context"
+	"errors"
+)
 
 // Customer is a struct that represents a customer
 type Customer struct {
@@ -10,13 +14,69 @@ type Customer struct {
 	Condition bool
 }
 
+// CustomerConditionTotal is a struct that represents the amount invoiced to customers with the same condition
+type CustomerConditionTotal struct {
+	Condition bool
+	Total     float64
+}
+
+// CustomerSpent is a struct that represents a customer with the amount spent in invoices
+type CustomerSpent struct {
+	Id        int
+	FirstName string
+	LastName  string
+	Amount    float64
+}
+
+// CustomerFields are the fields of a customer that ReadPage can filter and sort by
+var CustomerFields = query.Fields{
+	"id":         query.KindInt,
+	"first_name": query.KindString,
+	"last_name":  query.KindString,
+	"condition":  query.KindBool,
+}
+
+// CustomerValue returns the value of a field of CustomerFields of the customer (the value of its column in the sql storages)
+func CustomerValue(c *Customer, field string) any {
+	switch field {
+	case "id":
+		return c.Id
+	case "first_name":
+		return c.FirstName
+	case "last_name":
+		return c.LastName
+	case "condition":
+		return c.Condition
+	}
+	return nil
+}
+
 // StorageCustomer is an interface that represents a customer storage
 type StorageCustomer interface {
 	// ReadAll returns all customers
-	ReadAll() (cs []*Customer, err error)
+	ReadAll(ctx context.Context) (cs []*Customer, err error)
+
+	// ReadPage returns the page of customers of the query and the number of customers that match its filters
+	ReadPage(ctx context.Context, q query.Query) (cs []*Customer, total int, err error)
+
+	// ReadById returns the customer with the given id
+	ReadById(ctx context.Context, id int) (c *Customer, err error)
+
+	// Create inserts a new customer (the id is kept if set, otherwise it is generated)
+	Create(ctx context.Context, c *Customer) (err error)
+
+	// Update replaces the customer with the same id
+	Update(ctx context.Context, c *Customer) (err error)
+
+	// Delete removes the customer with the given id
+	Delete(ctx context.Context, id int) (err error)
+
+	// ReadTotalByCondition returns the sum of the invoice totals grouped by customer condition (rounded to two decimals)
+	ReadTotalByCondition(ctx context.Context) (ts []*CustomerConditionTotal, err error)
 
-	// Create inserts a new customer
-	Create(c *Customer) (err error)
+	// ReadTopSpenders returns the limit customers with the highest sum of invoice totals,
+	// filtered by condition when it is not nil
+	ReadTopSpenders(ctx context.Context, limit int, condition *bool) (cs []*CustomerSpent, err error)
 }
 
 var (
@@ -24,4 +84,6 @@ var (
 	ErrStorageCustomerInternal = errors.New("internal storage error")
 	// ErrStorageCustomerNotFound is returned when a customer is not found
 	ErrStorageCustomerNotFound = errors.New("customer not found")
+	// ErrStorageCustomerReferenced is returned when a customer can not be deleted because invoices reference it
+	ErrStorageCustomerReferenced = errors.New("customer referenced by invoices")
 )
\ No newline at end of file
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"app/internal/jsondb"
+	"app/internal/memdb"
+	"app/internal/query"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// NewStorageCustomerJSON returns a new instance of StorageCustomerJSON for the json file at path.
+// The related tables are read from the files with their canonical names in the same directory (see jsondb.FilesFor)
+func NewStorageCustomerJSON(path string) *StorageCustomerJSON {
+	return &StorageCustomerJSON{db: jsondb.New(jsondb.FilesFor(jsondb.TableCustomers, path))}
+}
+
+// StorageCustomerJSON is a struct that represents a customer storage in a json file for StorageCustomer interface.
+// Each operation works on the data loaded from the files, with the same semantics as StorageCustomerMap
+type StorageCustomerJSON struct {
+	db *jsondb.DB
+}
+
+// ReadAll returns all customers
+func (s *StorageCustomerJSON) ReadAll(ctx context.Context) (cs []*Customer, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		cs, err = NewStorageCustomerMap(mdb).ReadAll(ctx)
+		return
+	})
+	err = customerJSONError(err)
+	return
+}
+
+// ReadPage returns the page of customers of the query and the number of customers that match its filters
+func (s *StorageCustomerJSON) ReadPage(ctx context.Context, q query.Query) (cs []*Customer, total int, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		cs, total, err = NewStorageCustomerMap(mdb).ReadPage(ctx, q)
+		return
+	})
+	err = customerJSONError(err)
+	return
+}
+
+// ReadById returns the customer with the given id
+func (s *StorageCustomerJSON) ReadById(ctx context.Context, id int) (c *Customer, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		c, err = NewStorageCustomerMap(mdb).ReadById(ctx, id)
+		return
+	})
+	err = customerJSONError(err)
+	return
+}
+
+// Create inserts a new customer (the id is kept if set, otherwise it is generated)
+func (s *StorageCustomerJSON) Create(ctx context.Context, c *Customer) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageCustomerMap(mdb).Create(ctx, c)
+		return
+	}, jsondb.TableCustomers)
+	err = customerJSONError(err)
+	return
+}
+
+// Update replaces the customer with the same id
+func (s *StorageCustomerJSON) Update(ctx context.Context, c *Customer) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageCustomerMap(mdb).Update(ctx, c)
+		return
+	}, jsondb.TableCustomers)
+	err = customerJSONError(err)
+	return
+}
+
+// Delete removes the customer with the given id
+func (s *StorageCustomerJSON) Delete(ctx context.Context, id int) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageCustomerMap(mdb).Delete(ctx, id)
+		return
+	}, jsondb.TableCustomers)
+	err = customerJSONError(err)
+	return
+}
+
+// ReadTotalByCondition returns the sum of the invoice totals grouped by customer condition (rounded to two decimals)
+func (s *StorageCustomerJSON) ReadTotalByCondition(ctx context.Context) (ts []*CustomerConditionTotal, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		ts, err = NewStorageCustomerMap(mdb).ReadTotalByCondition(ctx)
+		return
+	})
+	err = customerJSONError(err)
+	return
+}
+
+// ReadTopSpenders returns the limit customers with the highest sum of invoice totals,
+// filtered by condition when it is not nil
+func (s *StorageCustomerJSON) ReadTopSpenders(ctx context.Context, limit int, condition *bool) (cs []*CustomerSpent, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		cs, err = NewStorageCustomerMap(mdb).ReadTopSpenders(ctx, limit, condition)
+		return
+	})
+	err = customerJSONError(err)
+	return
+}
+
+// customerJSONError wraps the errors of the json files as internal storage errors
+func customerJSONError(err error) error {
+	if errors.Is(err, jsondb.ErrJSONDBFile) {
+		return fmt.Errorf("%w. %v", ErrStorageCustomerInternal, err)
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"app/internal/memdb"
+	"app/internal/query"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NewStorageCustomerMap returns a new instance of StorageCustomerMap
+func NewStorageCustomerMap(db *memdb.DB) *StorageCustomerMap {
+	return &StorageCustomerMap{db}
+}
+
+// StorageCustomerMap is a struct that represents a customer storage in memory for StorageCustomer interface
+type StorageCustomerMap struct {
+	db *memdb.DB
+}
+
+// ReadAll returns all customers
+func (s *StorageCustomerMap) ReadAll(ctx context.Context) (cs []*Customer, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		for _, row := range t.Customers {
+			cs = append(cs, customerFromRow(row))
+		}
+		return
+	})
+
+	// sort by id (primary key order)
+	sort.Slice(cs, func(i, j int) bool { return cs[i].Id < cs[j].Id })
+	return
+}
+
+// ReadPage returns the page of customers of the query and the number of customers that match its filters
+func (s *StorageCustomerMap) ReadPage(ctx context.Context, q query.Query) (cs []*Customer, total int, err error) {
+	cs, err = s.ReadAll(ctx)
+	if err != nil {
+		return
+	}
+
+	cs, total = query.Apply(cs, q, CustomerValue)
+	return
+}
+
+// ReadById returns the customer with the given id
+func (s *StorageCustomerMap) ReadById(ctx context.Context, id int) (c *Customer, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		row, ok := t.Customers[id]
+		if !ok {
+			err = ErrStorageCustomerNotFound
+			return
+		}
+
+		c = customerFromRow(row)
+		return
+	})
+	return
+}
+
+// Create inserts a new customer (the id is kept if set, otherwise it is generated)
+func (s *StorageCustomerMap) Create(ctx context.Context, c *Customer) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		// check duplicated id
+		if _, ok := t.Customers[c.Id]; ok {
+			err = fmt.Errorf("%w. duplicated id %d", ErrStorageCustomerInternal, c.Id)
+			return
+		}
+
+		// insert
+		c.Id = t.CustomerId(c.Id)
+		t.Customers[c.Id] = customerToRow(c)
+		return
+	})
+	return
+}
+
+// Update replaces the customer with the same id
+func (s *StorageCustomerMap) Update(ctx context.Context, c *Customer) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		if _, ok := t.Customers[c.Id]; !ok {
+			err = ErrStorageCustomerNotFound
+			return
+		}
+
+		t.Customers[c.Id] = customerToRow(c)
+		return
+	})
+	return
+}
+
+// Delete removes the customer with the given id
+func (s *StorageCustomerMap) Delete(ctx context.Context, id int) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		if _, ok := t.Customers[id]; !ok {
+			err = ErrStorageCustomerNotFound
+			return
+		}
+
+		// restrict: invoices reference the customer
+		for _, inv := range t.Invoices {
+			if inv.CustomerId == id {
+				err = fmt.Errorf("%w. invoice %d", ErrStorageCustomerReferenced, inv.Id)
+				return
+			}
+		}
+
+		delete(t.Customers, id)
+		return
+	})
+	return
+}
+
+// ReadTotalByCondition returns the sum of the invoice totals grouped by customer condition (rounded to two decimals)
+func (s *StorageCustomerMap) ReadTotalByCondition(ctx context.Context) (ts []*CustomerConditionTotal, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		// group (every condition with customers is reported, even without invoices)
+		totals := make(map[bool]float64)
+		for _, c := range t.Customers {
+			totals[c.Condition] += 0
+		}
+		for _, inv := range t.Invoices {
+			c, ok := t.Customers[inv.CustomerId]
+			if !ok {
+				continue
+			}
+			totals[c.Condition] += inv.Total
+		}
+
+		// condition true first
+		for _, cond := range []bool{true, false} {
+			if total, ok := totals[cond]; ok {
+				ts = append(ts, &CustomerConditionTotal{Condition: cond, Total: math.Round(total*100) / 100})
+			}
+		}
+		return
+	})
+	return
+}
+
+// ReadTopSpenders returns the limit customers with the highest sum of invoice totals,
+// filtered by condition when it is not nil
+func (s *StorageCustomerMap) ReadTopSpenders(ctx context.Context, limit int, condition *bool) (cs []*CustomerSpent, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		// group (only customers with invoices)
+		amounts := make(map[int]float64)
+		for _, inv := range t.Invoices {
+			c, ok := t.Customers[inv.CustomerId]
+			if !ok || (condition != nil && c.Condition != *condition) {
+				continue
+			}
+			amounts[c.Id] += inv.Total
+		}
+
+		for id, amount := range amounts {
+			c := t.Customers[id]
+			cs = append(cs, &CustomerSpent{Id: c.Id, FirstName: c.FirstName, LastName: c.LastName, Amount: math.Round(amount*100) / 100})
+		}
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	// rank
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].Amount != cs[j].Amount {
+			return cs[i].Amount > cs[j].Amount
+		}
+		return cs[i].Id < cs[j].Id
+	})
+	if len(cs) > limit {
+		cs = cs[:limit]
+	}
+	return
+}
+
+// customerFromRow returns a customer with the values of the row
+func customerFromRow(row *memdb.CustomerRow) *Customer {
+	return &Customer{
+		Id:        row.Id,
+		FirstName: row.FirstName,
+		LastName:  row.LastName,
+		Condition: row.Condition,
+	}
+}
+
+// customerToRow returns a row with the values of the customer
+func customerToRow(c *Customer) *memdb.CustomerRow {
+	return &memdb.CustomerRow{
+		Id:        c.Id,
+		FirstName: c.FirstName,
+		LastName:  c.LastName,
+		Condition: c.Condition,
+	}
+}
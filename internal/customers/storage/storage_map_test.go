@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"app/internal/memdb"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StorageCustomerMap
+func TestStorageCustomerMap_Create(t *testing.T) {
+	t.Run("auto increment id and explicit id", func(t *testing.T) {
+		// arrange
+		st := NewStorageCustomerMap(memdb.NewDB())
+
+		// act
+		c1 := &Customer{FirstName: "Ike", LastName: "Fifield"}
+		err1 := st.Create(context.Background(), c1)
+		c2 := &Customer{Id: 10, FirstName: "Arel", LastName: "Saint", Condition: true}
+		err2 := st.Create(context.Background(), c2)
+		c3 := &Customer{FirstName: "Stefan", LastName: "Rolfe"}
+		err3 := st.Create(context.Background(), c3)
+		err4 := st.Create(context.Background(), &Customer{Id: 10})
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		require.Equal(t, 1, c1.Id)
+		require.Equal(t, 10, c2.Id)
+		require.Equal(t, 11, c3.Id)
+		require.ErrorIs(t, err4, ErrStorageCustomerInternal)
+	})
+}
+
+func TestStorageCustomerMap_ReadUpdateDelete(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		// arrange
+		st := NewStorageCustomerMap(memdb.NewDB())
+
+		// act
+		c, errRead := st.ReadById(context.Background(), 1)
+		errUpdate := st.Update(context.Background(), &Customer{Id: 1})
+		errDelete := st.Delete(context.Background(), 1)
+
+		// assert
+		require.Nil(t, c)
+		require.ErrorIs(t, errRead, ErrStorageCustomerNotFound)
+		require.ErrorIs(t, errUpdate, ErrStorageCustomerNotFound)
+		require.ErrorIs(t, errDelete, ErrStorageCustomerNotFound)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		// arrange
+		st := NewStorageCustomerMap(memdb.NewDB())
+		c := &Customer{FirstName: "Ike", LastName: "Fifield"}
+		require.NoError(t, st.Create(context.Background(), c))
+
+		// act
+		c.Condition = true
+		errUpdate := st.Update(context.Background(), c)
+		read, errRead := st.ReadById(context.Background(), c.Id)
+		errDelete := st.Delete(context.Background(), c.Id)
+		all, errAll := st.ReadAll(context.Background())
+
+		// assert
+		require.NoError(t, errUpdate)
+		require.NoError(t, errRead)
+		require.Equal(t, c, read)
+		require.NoError(t, errDelete)
+		require.NoError(t, errAll)
+		require.Empty(t, all)
+	})
+
+	t.Run("delete restricted by invoices", func(t *testing.T) {
+		// arrange
+		db := memdb.NewDB()
+		st := NewStorageCustomerMap(db)
+		require.NoError(t, st.Create(context.Background(), &Customer{Id: 1}))
+		_ = db.Update(func(t *memdb.Tables) (err error) {
+			t.Invoices[1] = &memdb.InvoiceRow{Id: 1, CustomerId: 1}
+			return
+		})
+
+		// act
+		err := st.Delete(context.Background(), 1)
+
+		// assert
+		require.ErrorIs(t, err, ErrStorageCustomerReferenced)
+	})
+}
+
+func TestStorageCustomerMap_Reports(t *testing.T) {
+	// arrange
+	db := memdb.NewDB()
+	_ = db.Update(func(t *memdb.Tables) (err error) {
+		t.Customers[1] = &memdb.CustomerRow{Id: 1, FirstName: "Ike", Condition: true}
+		t.Customers[2] = &memdb.CustomerRow{Id: 2, FirstName: "Arel", Condition: true}
+		t.Customers[3] = &memdb.CustomerRow{Id: 3, FirstName: "Stefan", Condition: false}
+		t.Invoices[1] = &memdb.InvoiceRow{Id: 1, CustomerId: 1, Total: 10.105}
+		t.Invoices[2] = &memdb.InvoiceRow{Id: 2, CustomerId: 1, Total: 5}
+		t.Invoices[3] = &memdb.InvoiceRow{Id: 3, CustomerId: 2, Total: 20}
+		t.Invoices[4] = &memdb.InvoiceRow{Id: 4, CustomerId: 3, Total: 1.5}
+		return
+	})
+	st := NewStorageCustomerMap(db)
+
+	t.Run("total by condition", func(t *testing.T) {
+		// act
+		ts, err := st.ReadTotalByCondition(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []*CustomerConditionTotal{
+			{Condition: true, Total: 35.11},
+			{Condition: false, Total: 1.5},
+		}, ts)
+	})
+
+	t.Run("top spenders filtered by condition", func(t *testing.T) {
+		// arrange
+		active := true
+
+		// act
+		cs, err := st.ReadTopSpenders(context.Background(), 1, &active)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []*CustomerSpent{{Id: 2, FirstName: "Arel", Amount: 20}}, cs)
+	})
+}
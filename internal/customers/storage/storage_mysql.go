@@ -1,8 +1,14 @@
 package storage
 
 import (
+	"app/internal/query"
+	"app/internal/sqltx"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 // NewStorageCustomerMySQL returns a new instance of StorageCustomerMySQL
@@ -18,32 +24,68 @@ type CustomerMySQL struct {
 	Condition	sql.NullBool
 }
 
+// customerColumns are the columns (or expressions) of the CustomerFields, also used by StorageCustomerSQLite.
+// Null values are read as zero values, as in the serialization of the rows
+var customerColumns = map[string]string{
+	"id":         "id",
+	"first_name": "COALESCE(first_name, '')",
+	"last_name":  "COALESCE(last_name, '')",
+	"condition":  "COALESCE(`condition`, 0)",
+}
+
 // StorageCustomerMySQL is a struct that represents a customer storage in MySQL for StorageCustomer interface
 type StorageCustomerMySQL struct {
-	db *sql.DB
+	// db is the database or the transaction the operations run on
+	db sqltx.Executor
+}
+
+// InTx returns a copy of the storage whose operations run in the transaction tx (see sqltx.Runner)
+func (s *StorageCustomerMySQL) InTx(tx *sql.Tx) *StorageCustomerMySQL {
+	return &StorageCustomerMySQL{db: tx}
 }
 
 // ReadAll returns all customers
-func (s *StorageCustomerMySQL) ReadAll() (cs []*Customer, err error) {
-	// query
-	query := "SELECT id, first_name, last_name, `condition` FROM customers"
-	
+func (s *StorageCustomerMySQL) ReadAll(ctx context.Context) (cs []*Customer, err error) {
+	cs, err = s.read(ctx, "SELECT id, first_name, last_name, `condition` FROM customers")
+	return
+}
+
+// ReadPage returns the page of customers of the query and the number of customers that match its filters
+func (s *StorageCustomerMySQL) ReadPage(ctx context.Context, q query.Query) (cs []*Customer, total int, err error) {
+	where, whereArgs := q.Where(customerColumns)
+	page, pageArgs := q.Page(customerColumns)
+
+	// count
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM customers"+where, whereArgs...).Scan(&total)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	// page
+	cs, err = s.read(ctx, "SELECT id, first_name, last_name, `condition` FROM customers"+where+page, append(whereArgs, pageArgs...)...)
+	return
+}
+
+// read returns the customers selected by the query with the args
+func (s *StorageCustomerMySQL) read(ctx context.Context, query string, args ...any) (cs []*Customer, err error) {
 	// prepare statement
 	var stmt *sql.Stmt
-	stmt, err = s.db.Prepare(query)
+	stmt, err = s.db.PrepareContext(ctx, query)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageCustomerInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
 		return
 	}
 	defer stmt.Close()
 
 	// execute query
 	var rows *sql.Rows
-	rows, err = stmt.Query()
+	rows, err = stmt.QueryContext(ctx, args...)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageCustomerInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
 		return
 	}
+	defer rows.Close()
 
 	// iterate rows
 	for rows.Next() {
@@ -51,7 +93,7 @@ func (s *StorageCustomerMySQL) ReadAll() (cs []*Customer, err error) {
 		var csMySQL CustomerMySQL
 		err = rows.Scan(&csMySQL.Id, &csMySQL.FirstName, &csMySQL.LastName, &csMySQL.Condition)
 		if err != nil {
-			err = fmt.Errorf("%w. %v", ErrStorageCustomerInternal, err)
+			err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
 			return
 		}
 
@@ -73,14 +115,67 @@ func (s *StorageCustomerMySQL) ReadAll() (cs []*Customer, err error) {
 		// append customer
 		cs = append(cs, c)
 	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	return
+}
+
+// ReadById returns the customer with the given id
+func (s *StorageCustomerMySQL) ReadById(ctx context.Context, id int) (c *Customer, err error) {
+	// query
+	query := "SELECT id, first_name, last_name, `condition` FROM customers WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var csMySQL CustomerMySQL
+	err = stmt.QueryRowContext(ctx, id).Scan(&csMySQL.Id, &csMySQL.FirstName, &csMySQL.LastName, &csMySQL.Condition)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w. %v", ErrStorageCustomerNotFound, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	// serialization
+	c = new(Customer)
+	if csMySQL.Id.Valid {
+		c.Id = int(csMySQL.Id.Int32)
+	}
+	if csMySQL.FirstName.Valid {
+		c.FirstName = csMySQL.FirstName.String
+	}
+	if csMySQL.LastName.Valid {
+		c.LastName = csMySQL.LastName.String
+	}
+	if csMySQL.Condition.Valid {
+		c.Condition = csMySQL.Condition.Bool
+	}
 
 	return
 }
 
 // Create inserts a new customer
-func (s *StorageCustomerMySQL) Create(c *Customer) (err error) {
+func (s *StorageCustomerMySQL) Create(ctx context.Context, c *Customer) (err error) {
 	// deserialization
 	var csMySQL CustomerMySQL
+	if c.Id != 0 {
+		csMySQL.Id.Valid = true
+		csMySQL.Id.Int32 = int32(c.Id)
+	}
 	if c.FirstName != "" {
 		csMySQL.FirstName.Valid = true
 		csMySQL.FirstName.String = c.FirstName
@@ -89,28 +184,26 @@ func (s *StorageCustomerMySQL) Create(c *Customer) (err error) {
 		csMySQL.LastName.Valid = true
 		csMySQL.LastName.String = c.LastName
 	}
-	if c.Condition {
-		csMySQL.Condition.Valid = true
-		csMySQL.Condition.Bool = c.Condition
-	}
+	csMySQL.Condition.Valid = true
+	csMySQL.Condition.Bool = c.Condition
 
 	// query
-	query := "INSERT INTO customers (first_name, last_name, `condition`) VALUES (?, ?, ?)"
+	query := "INSERT INTO customers (id, first_name, last_name, `condition`) VALUES (?, ?, ?, ?)"
 
 	// prepare statement
 	var stmt *sql.Stmt
-	stmt, err = s.db.Prepare(query)
+	stmt, err = s.db.PrepareContext(ctx, query)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageCustomerInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
 		return
 	}
 	defer stmt.Close()
 
 	// execute query
 	var result sql.Result
-	result, err = stmt.Exec(csMySQL.FirstName, csMySQL.LastName, csMySQL.Condition)
+	result, err = stmt.ExecContext(ctx, csMySQL.Id, csMySQL.FirstName, csMySQL.LastName, csMySQL.Condition)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageCustomerInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
 		return
 	}
 
@@ -118,7 +211,7 @@ func (s *StorageCustomerMySQL) Create(c *Customer) (err error) {
 	var rowsAffected int64
 	rowsAffected, err = result.RowsAffected()
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageCustomerInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
 		return
 	}
 	if rowsAffected != 1 {
@@ -130,7 +223,7 @@ func (s *StorageCustomerMySQL) Create(c *Customer) (err error) {
 	var lastInsertId int64
 	lastInsertId, err = result.LastInsertId()
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageCustomerInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
 		return
 	}
 
@@ -139,3 +232,208 @@ func (s *StorageCustomerMySQL) Create(c *Customer) (err error) {
 
 	return
 }
+
+// Update replaces the customer with the same id
+func (s *StorageCustomerMySQL) Update(ctx context.Context, c *Customer) (err error) {
+	// deserialization
+	var csMySQL CustomerMySQL
+	if c.FirstName != "" {
+		csMySQL.FirstName.Valid = true
+		csMySQL.FirstName.String = c.FirstName
+	}
+	if c.LastName != "" {
+		csMySQL.LastName.Valid = true
+		csMySQL.LastName.String = c.LastName
+	}
+	csMySQL.Condition.Valid = true
+	csMySQL.Condition.Bool = c.Condition
+
+	// query
+	query := "UPDATE customers SET first_name = ?, last_name = ?, `condition` = ? WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, csMySQL.FirstName, csMySQL.LastName, csMySQL.Condition, c.Id)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	// check rows affected (0 when the customer does not exist or nothing changed)
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		_, err = s.ReadById(ctx, c.Id)
+		return
+	}
+
+	return
+}
+
+// Delete removes the customer with the given id
+func (s *StorageCustomerMySQL) Delete(ctx context.Context, id int) (err error) {
+	// query
+	query := "DELETE FROM customers WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1451 {
+			err = fmt.Errorf("%w. %v", ErrStorageCustomerReferenced, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		err = ErrStorageCustomerNotFound
+		return
+	}
+
+	return
+}
+
+// ReadTotalByCondition returns the sum of the invoice totals grouped by customer condition (rounded to two decimals)
+func (s *StorageCustomerMySQL) ReadTotalByCondition(ctx context.Context) (ts []*CustomerConditionTotal, err error) {
+	// query (customers without condition are counted as inactive)
+	query := "SELECT COALESCE(c.`condition`, 0) AS cond, ROUND(COALESCE(SUM(i.total), 0), 2) AS total " +
+		"FROM customers c LEFT JOIN invoices i ON i.customer_id = c.id " +
+		"GROUP BY cond ORDER BY cond DESC"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var rows *sql.Rows
+	rows, err = stmt.QueryContext(ctx)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer rows.Close()
+
+	// iterate rows
+	for rows.Next() {
+		// scan row
+		var t CustomerConditionTotal
+		err = rows.Scan(&t.Condition, &t.Total)
+		if err != nil {
+			err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+			return
+		}
+
+		// append total
+		ts = append(ts, &t)
+	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	return
+}
+
+// ReadTopSpenders returns the limit customers with the highest sum of invoice totals,
+// filtered by condition when it is not nil
+func (s *StorageCustomerMySQL) ReadTopSpenders(ctx context.Context, limit int, condition *bool) (cs []*CustomerSpent, err error) {
+	// query
+	query := "SELECT c.id, c.first_name, c.last_name, ROUND(COALESCE(SUM(i.total), 0), 2) AS amount " +
+		"FROM customers c INNER JOIN invoices i ON i.customer_id = c.id"
+	args := make([]any, 0, 2)
+	if condition != nil {
+		query += " WHERE COALESCE(c.`condition`, 0) = ?"
+		args = append(args, *condition)
+	}
+	query += " GROUP BY c.id, c.first_name, c.last_name ORDER BY amount DESC, c.id LIMIT ?"
+	args = append(args, limit)
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var rows *sql.Rows
+	rows, err = stmt.QueryContext(ctx, args...)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer rows.Close()
+
+	// iterate rows
+	for rows.Next() {
+		// scan row
+		var csMySQL CustomerMySQL
+		var amount float64
+		err = rows.Scan(&csMySQL.Id, &csMySQL.FirstName, &csMySQL.LastName, &amount)
+		if err != nil {
+			err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+			return
+		}
+
+		// serialization
+		c := &CustomerSpent{Amount: amount}
+		if csMySQL.Id.Valid {
+			c.Id = int(csMySQL.Id.Int32)
+		}
+		if csMySQL.FirstName.Valid {
+			c.FirstName = csMySQL.FirstName.String
+		}
+		if csMySQL.LastName.Valid {
+			c.LastName = csMySQL.LastName.String
+		}
+
+		// append customer
+		cs = append(cs, c)
+	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,429 @@
+package storage
+
+import (
+	"app/internal/query"
+	"app/internal/sqlitedb"
+	"app/internal/sqltx"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// NewStorageCustomerSQLite returns a new instance of StorageCustomerSQLite
+func NewStorageCustomerSQLite(db *sql.DB) *StorageCustomerSQLite {
+	return &StorageCustomerSQLite{db}
+}
+
+// CustomerSQLite is a struct that represents a customer in SQLite
+type CustomerSQLite struct {
+	Id			sql.NullInt32
+	FirstName	sql.NullString
+	LastName	sql.NullString
+	Condition	sql.NullBool
+}
+
+// StorageCustomerSQLite is a struct that represents a customer storage in SQLite for StorageCustomer interface
+type StorageCustomerSQLite struct {
+	// db is the database or the transaction the operations run on
+	db sqltx.Executor
+}
+
+// InTx returns a copy of the storage whose operations run in the transaction tx (see sqltx.Runner)
+func (s *StorageCustomerSQLite) InTx(tx *sql.Tx) *StorageCustomerSQLite {
+	return &StorageCustomerSQLite{db: tx}
+}
+
+// ReadAll returns all customers
+func (s *StorageCustomerSQLite) ReadAll(ctx context.Context) (cs []*Customer, err error) {
+	cs, err = s.read(ctx, "SELECT id, first_name, last_name, `condition` FROM customers")
+	return
+}
+
+// ReadPage returns the page of customers of the query and the number of customers that match its filters
+func (s *StorageCustomerSQLite) ReadPage(ctx context.Context, q query.Query) (cs []*Customer, total int, err error) {
+	where, whereArgs := q.Where(customerColumns)
+	page, pageArgs := q.Page(customerColumns)
+
+	// count
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM customers"+where, whereArgs...).Scan(&total)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	// page
+	cs, err = s.read(ctx, "SELECT id, first_name, last_name, `condition` FROM customers"+where+page, append(whereArgs, pageArgs...)...)
+	return
+}
+
+// read returns the customers selected by the query with the args
+func (s *StorageCustomerSQLite) read(ctx context.Context, query string, args ...any) (cs []*Customer, err error) {
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var rows *sql.Rows
+	rows, err = stmt.QueryContext(ctx, args...)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer rows.Close()
+
+	// iterate rows
+	for rows.Next() {
+		// scan row
+		var csSQLite CustomerSQLite
+		err = rows.Scan(&csSQLite.Id, &csSQLite.FirstName, &csSQLite.LastName, &csSQLite.Condition)
+		if err != nil {
+			err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+			return
+		}
+
+		// serialization
+		c := new(Customer)
+		if csSQLite.Id.Valid {
+			c.Id = int(csSQLite.Id.Int32)
+		}
+		if csSQLite.FirstName.Valid {
+			c.FirstName = csSQLite.FirstName.String
+		}
+		if csSQLite.LastName.Valid {
+			c.LastName = csSQLite.LastName.String
+		}
+		if csSQLite.Condition.Valid {
+			c.Condition = csSQLite.Condition.Bool
+		}
+
+		// append customer
+		cs = append(cs, c)
+	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	return
+}
+
+// ReadById returns the customer with the given id
+func (s *StorageCustomerSQLite) ReadById(ctx context.Context, id int) (c *Customer, err error) {
+	// query
+	query := "SELECT id, first_name, last_name, `condition` FROM customers WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var csSQLite CustomerSQLite
+	err = stmt.QueryRowContext(ctx, id).Scan(&csSQLite.Id, &csSQLite.FirstName, &csSQLite.LastName, &csSQLite.Condition)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w. %v", ErrStorageCustomerNotFound, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	// serialization
+	c = new(Customer)
+	if csSQLite.Id.Valid {
+		c.Id = int(csSQLite.Id.Int32)
+	}
+	if csSQLite.FirstName.Valid {
+		c.FirstName = csSQLite.FirstName.String
+	}
+	if csSQLite.LastName.Valid {
+		c.LastName = csSQLite.LastName.String
+	}
+	if csSQLite.Condition.Valid {
+		c.Condition = csSQLite.Condition.Bool
+	}
+
+	return
+}
+
+// Create inserts a new customer
+func (s *StorageCustomerSQLite) Create(ctx context.Context, c *Customer) (err error) {
+	// deserialization
+	var csSQLite CustomerSQLite
+	if c.Id != 0 {
+		csSQLite.Id.Valid = true
+		csSQLite.Id.Int32 = int32(c.Id)
+	}
+	if c.FirstName != "" {
+		csSQLite.FirstName.Valid = true
+		csSQLite.FirstName.String = c.FirstName
+	}
+	if c.LastName != "" {
+		csSQLite.LastName.Valid = true
+		csSQLite.LastName.String = c.LastName
+	}
+	csSQLite.Condition.Valid = true
+	csSQLite.Condition.Bool = c.Condition
+
+	// query
+	query := "INSERT INTO customers (id, first_name, last_name, `condition`) VALUES (?, ?, ?, ?)"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, csSQLite.Id, csSQLite.FirstName, csSQLite.LastName, csSQLite.Condition)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	if rowsAffected != 1 {
+		err = fmt.Errorf("%w. %s", ErrStorageCustomerInternal, "rows affected != 1")
+		return
+	}
+
+	// get last insert id
+	var lastInsertId int64
+	lastInsertId, err = result.LastInsertId()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	// set last insert id
+	c.Id = int(lastInsertId)
+
+	return
+}
+
+// Update replaces the customer with the same id
+func (s *StorageCustomerSQLite) Update(ctx context.Context, c *Customer) (err error) {
+	// deserialization
+	var csSQLite CustomerSQLite
+	if c.FirstName != "" {
+		csSQLite.FirstName.Valid = true
+		csSQLite.FirstName.String = c.FirstName
+	}
+	if c.LastName != "" {
+		csSQLite.LastName.Valid = true
+		csSQLite.LastName.String = c.LastName
+	}
+	csSQLite.Condition.Valid = true
+	csSQLite.Condition.Bool = c.Condition
+
+	// query
+	query := "UPDATE customers SET first_name = ?, last_name = ?, `condition` = ? WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, csSQLite.FirstName, csSQLite.LastName, csSQLite.Condition, c.Id)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	// check rows affected (0 when the customer does not exist or nothing changed)
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		_, err = s.ReadById(ctx, c.Id)
+		return
+	}
+
+	return
+}
+
+// Delete removes the customer with the given id
+func (s *StorageCustomerSQLite) Delete(ctx context.Context, id int) (err error) {
+	// query
+	query := "DELETE FROM customers WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		if sqlitedb.IsForeignKeyError(err) {
+			err = fmt.Errorf("%w. %v", ErrStorageCustomerReferenced, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		err = ErrStorageCustomerNotFound
+		return
+	}
+
+	return
+}
+
+// ReadTotalByCondition returns the sum of the invoice totals grouped by customer condition (rounded to two decimals)
+func (s *StorageCustomerSQLite) ReadTotalByCondition(ctx context.Context) (ts []*CustomerConditionTotal, err error) {
+	// query (customers without condition are counted as inactive)
+	query := "SELECT COALESCE(c.`condition`, 0) AS cond, ROUND(COALESCE(SUM(i.total), 0), 2) AS total " +
+		"FROM customers c LEFT JOIN invoices i ON i.customer_id = c.id " +
+		"GROUP BY cond ORDER BY cond DESC"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var rows *sql.Rows
+	rows, err = stmt.QueryContext(ctx)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer rows.Close()
+
+	// iterate rows
+	for rows.Next() {
+		// scan row
+		var t CustomerConditionTotal
+		err = rows.Scan(&t.Condition, &t.Total)
+		if err != nil {
+			err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+			return
+		}
+
+		// append total
+		ts = append(ts, &t)
+	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	return
+}
+
+// ReadTopSpenders returns the limit customers with the highest sum of invoice totals,
+// filtered by condition when it is not nil
+func (s *StorageCustomerSQLite) ReadTopSpenders(ctx context.Context, limit int, condition *bool) (cs []*CustomerSpent, err error) {
+	// query
+	query := "SELECT c.id, c.first_name, c.last_name, ROUND(COALESCE(SUM(i.total), 0), 2) AS amount " +
+		"FROM customers c INNER JOIN invoices i ON i.customer_id = c.id"
+	args := make([]any, 0, 2)
+	if condition != nil {
+		query += " WHERE COALESCE(c.`condition`, 0) = ?"
+		args = append(args, *condition)
+	}
+	query += " GROUP BY c.id, c.first_name, c.last_name ORDER BY amount DESC, c.id LIMIT ?"
+	args = append(args, limit)
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var rows *sql.Rows
+	rows, err = stmt.QueryContext(ctx, args...)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+	defer rows.Close()
+
+	// iterate rows
+	for rows.Next() {
+		// scan row
+		var csSQLite CustomerSQLite
+		var amount float64
+		err = rows.Scan(&csSQLite.Id, &csSQLite.FirstName, &csSQLite.LastName, &amount)
+		if err != nil {
+			err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+			return
+		}
+
+		// serialization
+		c := &CustomerSpent{Amount: amount}
+		if csSQLite.Id.Valid {
+			c.Id = int(csSQLite.Id.Int32)
+		}
+		if csSQLite.FirstName.Valid {
+			c.FirstName = csSQLite.FirstName.String
+		}
+		if csSQLite.LastName.Valid {
+			c.LastName = csSQLite.LastName.String
+		}
+
+		// append customer
+		cs = append(cs, c)
+	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageCustomerInternal, err)
+		return
+	}
+
+	return
+}
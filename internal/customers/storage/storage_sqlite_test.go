@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"app/internal/sqlitedb"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteDB returns an in-memory SQLite database with the schema
+func newSQLiteDB(t *testing.T) *sql.DB {
+	db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// Tests for StorageCustomerSQLite
+func TestStorageCustomerSQLite(t *testing.T) {
+	t.Run("create, read, update and delete", func(t *testing.T) {
+		// arrange
+		st := NewStorageCustomerSQLite(newSQLiteDB(t))
+
+		// act
+		c := &Customer{FirstName: "Ike", LastName: "Fifield"}
+		errCreate := st.Create(context.Background(), c)
+		c.Condition = true
+		errUpdate := st.Update(context.Background(), c)
+		read, errRead := st.ReadById(context.Background(), c.Id)
+		errDelete := st.Delete(context.Background(), c.Id)
+		_, errNotFound := st.ReadById(context.Background(), c.Id)
+
+		// assert
+		require.NoError(t, errCreate)
+		require.Equal(t, 1, c.Id)
+		require.NoError(t, errUpdate)
+		require.NoError(t, errRead)
+		require.Equal(t, c, read)
+		require.NoError(t, errDelete)
+		require.ErrorIs(t, errNotFound, ErrStorageCustomerNotFound)
+	})
+
+	t.Run("update and delete not found", func(t *testing.T) {
+		// arrange
+		st := NewStorageCustomerSQLite(newSQLiteDB(t))
+
+		// act
+		errUpdate := st.Update(context.Background(), &Customer{Id: 1, FirstName: "Ike"})
+		errDelete := st.Delete(context.Background(), 1)
+
+		// assert
+		require.ErrorIs(t, errUpdate, ErrStorageCustomerNotFound)
+		require.ErrorIs(t, errDelete, ErrStorageCustomerNotFound)
+	})
+
+	t.Run("delete restricted by invoices and reports", func(t *testing.T) {
+		// arrange
+		db := newSQLiteDB(t)
+		st := NewStorageCustomerSQLite(db)
+		require.NoError(t, st.Create(context.Background(), &Customer{Id: 1, FirstName: "Ike", Condition: true}))
+		require.NoError(t, st.Create(context.Background(), &Customer{Id: 2, FirstName: "Arel", Condition: false}))
+		_, err := db.Exec("INSERT INTO invoices (customer_id, total) VALUES (1, 10.5), (1, 2.25), (2, 4)")
+		require.NoError(t, err)
+
+		// act
+		errDelete := st.Delete(context.Background(), 1)
+		ts, errTotals := st.ReadTotalByCondition(context.Background())
+		active := true
+		cs, errTop := st.ReadTopSpenders(context.Background(), 5, &active)
+
+		// assert
+		require.ErrorIs(t, errDelete, ErrStorageCustomerReferenced)
+		require.NoError(t, errTotals)
+		require.Equal(t, []*CustomerConditionTotal{{Condition: true, Total: 12.75}, {Condition: false, Total: 4}}, ts)
+		require.NoError(t, errTop)
+		require.Equal(t, []*CustomerSpent{{Id: 1, FirstName: "Ike", Amount: 12.75}}, cs)
+	})
+}
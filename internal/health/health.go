@@ -0,0 +1,84 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Tables are the tables of docs/db/mysql/database.sql used by the storages and the authentication
+var Tables = []string{"customers", "products", "invoices", "sales", "api_keys"}
+
+// Status is a struct that represents the readiness of the database
+type Status struct {
+	// Ping is the error of the database ping (nil if it answered), or of the context if it ended while checking the tables
+	Ping error
+	// Tables maps each checked table to whether it exists (empty if the ping failed)
+	Tables map[string]bool
+	// Stats are the statistics of the connection pool
+	Stats sql.DBStats
+}
+
+// Checker is an interface that represents a check of the readiness of the database
+type Checker interface {
+	// Check returns the status of the database (also when it is not ready) and an error if it is not ready
+	Check(ctx context.Context) (s *Status, err error)
+}
+
+var (
+	// ErrHealthPing is returned when the database does not answer the ping
+	ErrHealthPing = errors.New("database ping failed")
+	// ErrHealthTable is returned when a table of the storages does not exist
+	ErrHealthTable = errors.New("database table missing")
+)
+
+// NewCheckerSQL is a constructor for a checker of the pool db and its tables
+func NewCheckerSQL(db *sql.DB, tables []string) *CheckerSQL {
+	return &CheckerSQL{db: db, tables: tables}
+}
+
+// CheckerSQL is a struct that checks a sql database (MySQL or SQLite)
+type CheckerSQL struct {
+	// db is the connection pool shared by the storages
+	db *sql.DB
+	// tables are the tables that must exist
+	tables []string
+}
+
+// Check pings the database and checks every table exists
+func (c *CheckerSQL) Check(ctx context.Context) (s *Status, err error) {
+	s = &Status{Tables: make(map[string]bool, len(c.tables))}
+	defer func() { s.Stats = c.db.Stats() }()
+
+	// ping
+	s.Ping = c.db.PingContext(ctx)
+	if s.Ping != nil {
+		err = fmt.Errorf("%w. %w", ErrHealthPing, s.Ping)
+		return
+	}
+
+	// tables (a query that reads no rows fails only if the table does not exist)
+	var missing []string
+	for _, table := range c.tables {
+		rows, e := c.db.QueryContext(ctx, "SELECT 1 FROM "+table+" LIMIT 0")
+		if e != nil {
+			if ctx.Err() != nil {
+				s.Ping = e
+				err = fmt.Errorf("%w. %w", ErrHealthPing, e)
+				return
+			}
+			s.Tables[table] = false
+			missing = append(missing, table)
+			continue
+		}
+		rows.Close()
+		s.Tables[table] = true
+	}
+	if len(missing) > 0 {
+		err = fmt.Errorf("%w. %v", ErrHealthTable, missing)
+		return
+	}
+
+	return
+}
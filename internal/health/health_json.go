@@ -0,0 +1,75 @@
+package health
+
+import (
+	"app/internal/jsondb"
+	"app/internal/memdb"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// NewCheckerJSON is a constructor for a checker of the json files of the tables in the directory dir
+func NewCheckerJSON(dir string, tables []string) *CheckerJSON {
+	return &CheckerJSON{
+		dir:    dir,
+		db:     jsondb.New(jsondb.FilesFor(jsondb.TableInvoices, filepath.Join(dir, jsondb.FileInvoices))),
+		tables: tables,
+	}
+}
+
+// CheckerJSON is a struct that checks a database of json files (the Stats of its status are always zero)
+type CheckerJSON struct {
+	// dir is the directory of the files
+	dir string
+	// db is the database of the files, loaded as the storages do
+	db *jsondb.DB
+	// tables are the tables whose file must be readable (a missing file is an empty table, as for the storages)
+	tables []string
+}
+
+// Check loads the files in the directory (the equivalent of a ping) and checks the file of every table is
+// a regular file or does not exist yet
+func (c *CheckerJSON) Check(ctx context.Context) (s *Status, err error) {
+	s = &Status{Tables: make(map[string]bool, len(c.tables))}
+
+	// load
+	s.Ping = c.load()
+	if s.Ping != nil {
+		err = fmt.Errorf("%w. %w", ErrHealthPing, s.Ping)
+		return
+	}
+
+	// tables
+	var missing []string
+	for _, table := range c.tables {
+		fi, e := os.Stat(filepath.Join(c.dir, table+".json"))
+		s.Tables[table] = errors.Is(e, fs.ErrNotExist) || (e == nil && fi.Mode().IsRegular())
+		if !s.Tables[table] {
+			missing = append(missing, table)
+		}
+	}
+	if len(missing) > 0 {
+		err = fmt.Errorf("%w. %v", ErrHealthTable, missing)
+		return
+	}
+
+	return
+}
+
+// load checks the directory exists and loads the files as the storages do
+func (c *CheckerJSON) load() (err error) {
+	fi, err := os.Stat(c.dir)
+	if err != nil {
+		return
+	}
+	if !fi.IsDir() {
+		err = fmt.Errorf("%s is not a directory", c.dir)
+		return
+	}
+
+	err = c.db.View(func(mdb *memdb.DB) (err error) { return })
+	return
+}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"app/internal/sqlitedb"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for CheckerSQL.Check method
+func TestCheckerSQL_Check(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		// arrange
+		db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+		require.NoError(t, err)
+		defer db.Close()
+		ch := NewCheckerSQL(db, Tables)
+
+		// act
+		s, err := ch.Check(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.NoError(t, s.Ping)
+		require.Equal(t, map[string]bool{"customers": true, "products": true, "invoices": true, "sales": true, "api_keys": true}, s.Tables)
+		require.Equal(t, 1, s.Stats.MaxOpenConnections)
+	})
+
+	t.Run("missing table", func(t *testing.T) {
+		// arrange
+		db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = db.Exec("DROP TABLE sales")
+		require.NoError(t, err)
+		ch := NewCheckerSQL(db, Tables)
+
+		// act
+		s, err := ch.Check(context.Background())
+
+		// assert
+		require.ErrorIs(t, err, ErrHealthTable)
+		require.ErrorContains(t, err, "[sales]")
+		require.False(t, s.Tables["sales"])
+		require.True(t, s.Tables["invoices"])
+	})
+
+	t.Run("closed database", func(t *testing.T) {
+		// arrange
+		db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+		require.NoError(t, err)
+		db.Close()
+		ch := NewCheckerSQL(db, Tables)
+
+		// act
+		s, err := ch.Check(context.Background())
+
+		// assert
+		require.ErrorIs(t, err, ErrHealthPing)
+		require.Error(t, s.Ping)
+		require.Empty(t, s.Tables)
+	})
+}
+
+// Tests for CheckerJSON.Check method
+func TestCheckerJSON_Check(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		for _, table := range Tables {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, table+".json"), []byte("[]"), 0o600))
+		}
+		ch := NewCheckerJSON(dir, Tables)
+
+		// act
+		s, err := ch.Check(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.NoError(t, s.Ping)
+		require.Equal(t, map[string]bool{"customers": true, "products": true, "invoices": true, "sales": true, "api_keys": true}, s.Tables)
+	})
+
+	t.Run("missing file is an empty table", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "customers.json"), []byte("[]"), 0o600))
+		ch := NewCheckerJSON(dir, []string{"customers", "api_keys"})
+
+		// act
+		s, err := ch.Check(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{"customers": true, "api_keys": true}, s.Tables)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		// arrange
+		ch := NewCheckerJSON(filepath.Join(t.TempDir(), "missing"), Tables)
+
+		// act
+		s, err := ch.Check(context.Background())
+
+		// assert
+		require.ErrorIs(t, err, ErrHealthPing)
+		require.Error(t, s.Ping)
+		require.Empty(t, s.Tables)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "sales.json"), []byte("{"), 0o600))
+		ch := NewCheckerJSON(dir, Tables)
+
+		// act
+		s, err := ch.Check(context.Background())
+
+		// assert
+		require.ErrorIs(t, err, ErrHealthPing)
+		require.Error(t, s.Ping)
+		require.Empty(t, s.Tables)
+	})
+}
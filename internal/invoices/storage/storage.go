@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"app/internal/query"
+	"context"
 	"errors"
 	"time"
 )
@@ -13,13 +15,82 @@ type Invoice struct {
 	CustomerId int
 }
 
+// InvoiceFields are the fields of an invoice that ReadPage can filter and sort by
+var InvoiceFields = query.Fields{
+	"id":          query.KindInt,
+	"datetime":    query.KindTime,
+	"total":       query.KindFloat,
+	"customer_id": query.KindInt,
+}
+
+// InvoiceValue returns the value of a field of InvoiceFields of the invoice (the value of its column in the sql storages)
+func InvoiceValue(i *Invoice, field string) any {
+	switch field {
+	case "id":
+		return i.Id
+	case "datetime":
+		return i.Datetime
+	case "total":
+		return i.Total
+	case "customer_id":
+		return i.CustomerId
+	}
+	return nil
+}
+
+// InvoiceFilter is a struct that represents the conditions of the invoices read by ReadPage (zero values are not applied)
+type InvoiceFilter struct {
+	CustomerId int
+	// From is the first datetime (inclusive)
+	From time.Time
+	// To is the datetime where the range ends (exclusive)
+	To       time.Time
+	MinTotal *float64
+	MaxTotal *float64
+}
+
+// Filters returns the query filters of the conditions
+func (f InvoiceFilter) Filters() (fs []query.Filter) {
+	if f.CustomerId != 0 {
+		fs = append(fs, query.Filter{Field: "customer_id", Op: query.OpEq, Value: f.CustomerId})
+	}
+	if !f.From.IsZero() {
+		fs = append(fs, query.Filter{Field: "datetime", Op: query.OpGte, Value: f.From})
+	}
+	if !f.To.IsZero() {
+		fs = append(fs, query.Filter{Field: "datetime", Op: query.OpLt, Value: f.To})
+	}
+	if f.MinTotal != nil {
+		fs = append(fs, query.Filter{Field: "total", Op: query.OpGte, Value: *f.MinTotal})
+	}
+	if f.MaxTotal != nil {
+		fs = append(fs, query.Filter{Field: "total", Op: query.OpLte, Value: *f.MaxTotal})
+	}
+	return
+}
+
 // StorageInvoice is an interface that represents a invoice storage
 type StorageInvoice interface {
 	// ReadAll returns all invoices
-	ReadAll() (is []*Invoice, err error)
+	ReadAll(ctx context.Context) (is []*Invoice, err error)
+
+	// ReadPage returns the page of invoices of the query and the number of invoices that match its filters
+	ReadPage(ctx context.Context, q query.Query) (is []*Invoice, total int, err error)
+
+	// ReadById returns the invoice with the given id
+	ReadById(ctx context.Context, id int) (i *Invoice, err error)
+
+	// Create inserts a new invoice (the id is kept if set, otherwise it is generated)
+	Create(ctx context.Context, i *Invoice) (err error)
+
+	// Update replaces the invoice with the same id
+	Update(ctx context.Context, i *Invoice) (err error)
+
+	// Delete removes the invoice with the given id
+	Delete(ctx context.Context, id int) (err error)
 
-	// Create inserts a new invoice
-	Create(i *Invoice) (err error)
+	// UpdateTotals sets the total of every invoice to the sum of its sales (quantity * product price)
+	UpdateTotals(ctx context.Context) (err error)
 }
 
 var (
@@ -27,6 +98,8 @@ var (
 	ErrStorageInvoiceInternal = errors.New("internal storage error")
 	// ErrStorageInvoiceNotFound is returned when a invoice is not found
 	ErrStorageInvoiceNotFound = errors.New("invoice not found")
+	// ErrStorageInvoiceReferenced is returned when an invoice can not be deleted because sales reference it
+	ErrStorageInvoiceReferenced = errors.New("invoice referenced by sales")
 	// ErrStorageInvoiceRelation is returned when a invoice relation is not found
 	ErrStorageInvoiceRelation = errors.New("invoice relation not found")
 )
\ No newline at end of file
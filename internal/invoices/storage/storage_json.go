@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"app/internal/jsondb"
+	"app/internal/memdb"
+	"app/internal/query"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// NewStorageInvoiceJSON returns a new instance of StorageInvoiceJSON for the json file at path.
+// The related tables are read from the files with their canonical names in the same directory (see jsondb.FilesFor)
+func NewStorageInvoiceJSON(path string) *StorageInvoiceJSON {
+	return &StorageInvoiceJSON{db: jsondb.New(jsondb.FilesFor(jsondb.TableInvoices, path))}
+}
+
+// StorageInvoiceJSON is a struct that represents an invoice storage in a json file for StorageInvoice interface.
+// Each operation works on the data loaded from the files, with the same semantics as StorageInvoiceMap
+type StorageInvoiceJSON struct {
+	db *jsondb.DB
+}
+
+// ReadAll returns all invoices
+func (s *StorageInvoiceJSON) ReadAll(ctx context.Context) (is []*Invoice, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		is, err = NewStorageInvoiceMap(mdb).ReadAll(ctx)
+		return
+	})
+	err = invoiceJSONError(err)
+	return
+}
+
+// ReadPage returns the page of invoices of the query and the number of invoices that match its filters
+func (s *StorageInvoiceJSON) ReadPage(ctx context.Context, q query.Query) (is []*Invoice, total int, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		is, total, err = NewStorageInvoiceMap(mdb).ReadPage(ctx, q)
+		return
+	})
+	err = invoiceJSONError(err)
+	return
+}
+
+// ReadById returns the invoice with the given id
+func (s *StorageInvoiceJSON) ReadById(ctx context.Context, id int) (i *Invoice, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		i, err = NewStorageInvoiceMap(mdb).ReadById(ctx, id)
+		return
+	})
+	err = invoiceJSONError(err)
+	return
+}
+
+// Create inserts a new invoice (the id is kept if set, otherwise it is generated)
+func (s *StorageInvoiceJSON) Create(ctx context.Context, i *Invoice) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageInvoiceMap(mdb).Create(ctx, i)
+		return
+	}, jsondb.TableInvoices)
+	err = invoiceJSONError(err)
+	return
+}
+
+// Update replaces the invoice with the same id
+func (s *StorageInvoiceJSON) Update(ctx context.Context, i *Invoice) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageInvoiceMap(mdb).Update(ctx, i)
+		return
+	}, jsondb.TableInvoices)
+	err = invoiceJSONError(err)
+	return
+}
+
+// Delete removes the invoice with the given id
+func (s *StorageInvoiceJSON) Delete(ctx context.Context, id int) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageInvoiceMap(mdb).Delete(ctx, id)
+		return
+	}, jsondb.TableInvoices)
+	err = invoiceJSONError(err)
+	return
+}
+
+// UpdateTotals sets the total of every invoice to the sum of its sales (quantity * product price)
+func (s *StorageInvoiceJSON) UpdateTotals(ctx context.Context) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageInvoiceMap(mdb).UpdateTotals(ctx)
+		return
+	}, jsondb.TableInvoices)
+	err = invoiceJSONError(err)
+	return
+}
+
+// invoiceJSONError wraps the errors of the json files as internal storage errors
+func invoiceJSONError(err error) error {
+	if errors.Is(err, jsondb.ErrJSONDBFile) {
+		return fmt.Errorf("%w. %v", ErrStorageInvoiceInternal, err)
+	}
+	return err
+}
@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"app/internal/memdb"
+	"app/internal/query"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NewStorageInvoiceMap returns a new instance of StorageInvoiceMap
+func NewStorageInvoiceMap(db *memdb.DB) *StorageInvoiceMap {
+	return &StorageInvoiceMap{db: db}
+}
+
+// StorageInvoiceMap is a struct that represents a invoice storage in memory for StorageInvoice interface
+type StorageInvoiceMap struct {
+	db *memdb.DB
+}
+
+// ReadAll returns all invoices
+func (s *StorageInvoiceMap) ReadAll(ctx context.Context) (is []*Invoice, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		for _, row := range t.Invoices {
+			is = append(is, invoiceFromRow(row))
+		}
+		return
+	})
+
+	// sort by id (primary key order)
+	sort.Slice(is, func(i, j int) bool { return is[i].Id < is[j].Id })
+	return
+}
+
+// ReadPage returns the page of invoices of the query and the number of invoices that match its filters
+func (s *StorageInvoiceMap) ReadPage(ctx context.Context, q query.Query) (is []*Invoice, total int, err error) {
+	is, err = s.ReadAll(ctx)
+	if err != nil {
+		return
+	}
+
+	is, total = query.Apply(is, q, InvoiceValue)
+	return
+}
+
+// ReadById returns the invoice with the given id
+func (s *StorageInvoiceMap) ReadById(ctx context.Context, id int) (i *Invoice, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		row, ok := t.Invoices[id]
+		if !ok {
+			err = ErrStorageInvoiceNotFound
+			return
+		}
+
+		i = invoiceFromRow(row)
+		return
+	})
+	return
+}
+
+// Create inserts a new invoice (the id is kept if set, otherwise it is generated)
+func (s *StorageInvoiceMap) Create(ctx context.Context, i *Invoice) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		// check duplicated id
+		if _, ok := t.Invoices[i.Id]; ok {
+			err = fmt.Errorf("%w. duplicated id %d", ErrStorageInvoiceInternal, i.Id)
+			return
+		}
+
+		// check relations
+		err = invoiceRelations(t, i)
+		if err != nil {
+			return
+		}
+
+		// insert
+		i.Id = t.InvoiceId(i.Id)
+		t.Invoices[i.Id] = invoiceToRow(i)
+		return
+	})
+	return
+}
+
+// Update replaces the invoice with the same id
+func (s *StorageInvoiceMap) Update(ctx context.Context, i *Invoice) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		if _, ok := t.Invoices[i.Id]; !ok {
+			err = ErrStorageInvoiceNotFound
+			return
+		}
+
+		// check relations
+		err = invoiceRelations(t, i)
+		if err != nil {
+			return
+		}
+
+		t.Invoices[i.Id] = invoiceToRow(i)
+		return
+	})
+	return
+}
+
+// Delete removes the invoice with the given id
+func (s *StorageInvoiceMap) Delete(ctx context.Context, id int) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		if _, ok := t.Invoices[id]; !ok {
+			err = ErrStorageInvoiceNotFound
+			return
+		}
+
+		// restrict: sales reference the invoice
+		for _, sa := range t.Sales {
+			if sa.InvoiceId == id {
+				err = fmt.Errorf("%w. sale %d", ErrStorageInvoiceReferenced, sa.Id)
+				return
+			}
+		}
+
+		delete(t.Invoices, id)
+		return
+	})
+	return
+}
+
+// UpdateTotals sets the total of every invoice to the sum of its sales (quantity * product price)
+func (s *StorageInvoiceMap) UpdateTotals(ctx context.Context) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		totals := make(map[int]float64, len(t.Invoices))
+		for _, sa := range t.Sales {
+			p, ok := t.Products[sa.ProductId]
+			if !ok {
+				continue
+			}
+			totals[sa.InvoiceId] += float64(sa.Quantity) * p.Price
+		}
+
+		for id, inv := range t.Invoices {
+			inv.Total = math.Round(totals[id]*100) / 100
+		}
+		return
+	})
+	return
+}
+
+// invoiceRelations checks that the customer of the invoice exists (a zero id is a null relation)
+func invoiceRelations(t *memdb.Tables, i *Invoice) (err error) {
+	if i.CustomerId == 0 {
+		return
+	}
+	if _, ok := t.Customers[i.CustomerId]; !ok {
+		err = fmt.Errorf("%w. customer %d", ErrStorageInvoiceRelation, i.CustomerId)
+		return
+	}
+	return
+}
+
+// invoiceFromRow returns an invoice with the values of the row
+func invoiceFromRow(row *memdb.InvoiceRow) *Invoice {
+	return &Invoice{
+		Id:         row.Id,
+		Datetime:   row.Datetime,
+		Total:      row.Total,
+		CustomerId: row.CustomerId,
+	}
+}
+
+// invoiceToRow returns a row with the values of the invoice
+func invoiceToRow(i *Invoice) *memdb.InvoiceRow {
+	return &memdb.InvoiceRow{
+		Id:         i.Id,
+		Datetime:   i.Datetime,
+		Total:      i.Total,
+		CustomerId: i.CustomerId,
+	}
+}
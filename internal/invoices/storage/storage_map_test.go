@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"app/internal/memdb"
+	"app/internal/query"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StorageInvoiceMap
+func TestStorageInvoiceMap_Create(t *testing.T) {
+	t.Run("customer relation", func(t *testing.T) {
+		// arrange
+		db := memdb.NewDB()
+		_ = db.Update(func(t *memdb.Tables) (err error) {
+			t.Customers[1] = &memdb.CustomerRow{Id: 1}
+			return
+		})
+		st := NewStorageInvoiceMap(db)
+
+		// act
+		errOk := st.Create(context.Background(), &Invoice{Datetime: time.Now(), CustomerId: 1})
+		errRelation := st.Create(context.Background(), &Invoice{Datetime: time.Now(), CustomerId: 2})
+
+		// assert
+		require.NoError(t, errOk)
+		require.ErrorIs(t, errRelation, ErrStorageInvoiceRelation)
+	})
+}
+
+func TestStorageInvoiceMap_UpdateTotals(t *testing.T) {
+	t.Run("sum of quantity * price", func(t *testing.T) {
+		// arrange
+		db := memdb.NewDB()
+		_ = db.Update(func(t *memdb.Tables) (err error) {
+			t.Products[1] = &memdb.ProductRow{Id: 1, Price: 1.5}
+			t.Products[2] = &memdb.ProductRow{Id: 2, Price: 10}
+			t.Invoices[1] = &memdb.InvoiceRow{Id: 1, Total: 99}
+			t.Invoices[2] = &memdb.InvoiceRow{Id: 2, Total: 99}
+			t.Sales[1] = &memdb.SaleRow{Id: 1, InvoiceId: 1, ProductId: 1, Quantity: 3}
+			t.Sales[2] = &memdb.SaleRow{Id: 2, InvoiceId: 1, ProductId: 2, Quantity: 2}
+			return
+		})
+		st := NewStorageInvoiceMap(db)
+
+		// act
+		err := st.UpdateTotals(context.Background())
+		inv1, _ := st.ReadById(context.Background(), 1)
+		inv2, _ := st.ReadById(context.Background(), 2)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 24.5, inv1.Total)
+		require.Equal(t, 0.0, inv2.Total)
+	})
+}
+
+func TestStorageInvoiceMap_ReadPage(t *testing.T) {
+	// arrange
+	db := memdb.NewDB()
+	_ = db.Update(func(t *memdb.Tables) (err error) {
+		t.Invoices[1] = &memdb.InvoiceRow{Id: 1, Datetime: time.Date(2022, 2, 28, 23, 59, 59, 0, time.UTC), Total: 10, CustomerId: 1}
+		t.Invoices[2] = &memdb.InvoiceRow{Id: 2, Datetime: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), Total: 20, CustomerId: 1}
+		t.Invoices[3] = &memdb.InvoiceRow{Id: 3, Datetime: time.Date(2022, 3, 31, 23, 59, 59, 0, time.UTC), Total: 30, CustomerId: 1}
+		t.Invoices[4] = &memdb.InvoiceRow{Id: 4, Datetime: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC), Total: 40, CustomerId: 1}
+		t.Invoices[5] = &memdb.InvoiceRow{Id: 5, Datetime: time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC), Total: 50, CustomerId: 2}
+		return
+	})
+	st := NewStorageInvoiceMap(db)
+	minTotal := 25.0
+
+	// act
+	march := InvoiceFilter{CustomerId: 1, From: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC), MinTotal: &minTotal}
+	is, total, err := st.ReadPage(context.Background(), query.Query{Filters: march.Filters()})
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, 3, is[0].Id)
+}
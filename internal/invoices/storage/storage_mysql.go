@@ -1,7 +1,11 @@
 package storage
 
 import (
+	"app/internal/query"
+	"app/internal/sqltx"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/go-sql-driver/mysql"
@@ -20,32 +24,68 @@ type InvoiceMySQL struct {
 	CustomerId sql.NullInt32
 }
 
+// invoiceColumns are the columns (or expressions) of the InvoiceFields, also used by StorageInvoiceSQLite.
+// Null values are read as zero values, as in the serialization of the rows
+var invoiceColumns = map[string]string{
+	"id":          "id",
+	"datetime":    "`datetime`",
+	"total":       "COALESCE(total, 0)",
+	"customer_id": "COALESCE(customer_id, 0)",
+}
+
 // StorageInvoiceMySQL is a struct that represents a invoice storage in MySQL for StorageInvoice interface
 type StorageInvoiceMySQL struct {
-	db *sql.DB
+	// db is the database or the transaction the operations run on
+	db sqltx.Executor
+}
+
+// InTx returns a copy of the storage whose operations run in the transaction tx (see sqltx.Runner)
+func (s *StorageInvoiceMySQL) InTx(tx *sql.Tx) *StorageInvoiceMySQL {
+	return &StorageInvoiceMySQL{db: tx}
 }
 
 // ReadAll returns all invoices
-func (s *StorageInvoiceMySQL) ReadAll() (is []*Invoice, err error) {
-	// query
-	query := "SELECT id, `datetime`, total, customer_id FROM invoices"
+func (s *StorageInvoiceMySQL) ReadAll(ctx context.Context) (is []*Invoice, err error) {
+	is, err = s.read(ctx, "SELECT id, `datetime`, total, customer_id FROM invoices")
+	return
+}
+
+// ReadPage returns the page of invoices of the query and the number of invoices that match its filters
+func (s *StorageInvoiceMySQL) ReadPage(ctx context.Context, q query.Query) (is []*Invoice, total int, err error) {
+	where, whereArgs := q.Where(invoiceColumns)
+	page, pageArgs := q.Page(invoiceColumns)
+
+	// count
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM invoices"+where, whereArgs...).Scan(&total)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
 
+	// page
+	is, err = s.read(ctx, "SELECT id, `datetime`, total, customer_id FROM invoices"+where+page, append(whereArgs, pageArgs...)...)
+	return
+}
+
+// read returns the invoices selected by the query with the args
+func (s *StorageInvoiceMySQL) read(ctx context.Context, query string, args ...any) (is []*Invoice, err error) {
 	// prepare statement
 	var stmt *sql.Stmt
-	stmt, err = s.db.Prepare(query)
+	stmt, err = s.db.PrepareContext(ctx, query)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageInvoiceInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
 		return
 	}
 	defer stmt.Close()
 
 	// execute query
 	var rows *sql.Rows
-	rows, err = stmt.Query()
+	rows, err = stmt.QueryContext(ctx, args...)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageInvoiceInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
 		return
 	}
+	defer rows.Close()
 
 	// iterate rows
 	for rows.Next() {
@@ -53,7 +93,7 @@ func (s *StorageInvoiceMySQL) ReadAll() (is []*Invoice, err error) {
 		var inMySQL InvoiceMySQL
 		err = rows.Scan(&inMySQL.Id, &inMySQL.Datetime, &inMySQL.Total, &inMySQL.CustomerId)
 		if err != nil {
-			err = fmt.Errorf("%w. %v", ErrStorageInvoiceInternal, err)
+			err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
 			return
 		}
 
@@ -75,14 +115,67 @@ func (s *StorageInvoiceMySQL) ReadAll() (is []*Invoice, err error) {
 		// append to slice
 		is = append(is, i)
 	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	return
+}
+
+// ReadById returns the invoice with the given id
+func (s *StorageInvoiceMySQL) ReadById(ctx context.Context, id int) (i *Invoice, err error) {
+	// query
+	query := "SELECT id, `datetime`, total, customer_id FROM invoices WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var inMySQL InvoiceMySQL
+	err = stmt.QueryRowContext(ctx, id).Scan(&inMySQL.Id, &inMySQL.Datetime, &inMySQL.Total, &inMySQL.CustomerId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w. %v", ErrStorageInvoiceNotFound, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	// serialization
+	i = new(Invoice)
+	if inMySQL.Id.Valid {
+		i.Id = int(inMySQL.Id.Int32)
+	}
+	if inMySQL.Datetime.Valid {
+		i.Datetime = inMySQL.Datetime.Time
+	}
+	if inMySQL.Total.Valid {
+		i.Total = inMySQL.Total.Float64
+	}
+	if inMySQL.CustomerId.Valid {
+		i.CustomerId = int(inMySQL.CustomerId.Int32)
+	}
 
 	return
 }
 
 // Create inserts a new invoice
-func (s *StorageInvoiceMySQL) Create(i *Invoice) (err error) {
+func (s *StorageInvoiceMySQL) Create(ctx context.Context, i *Invoice) (err error) {
 	// deserialization
 	var inMySQL InvoiceMySQL
+	if i.Id != (Invoice{}).Id {
+		inMySQL.Id.Valid = true
+		inMySQL.Id.Int32 = int32(i.Id)
+	}
 	if i.Datetime != (Invoice{}).Datetime {
 		inMySQL.Datetime.Valid = true
 		inMySQL.Datetime.Time = i.Datetime
@@ -97,20 +190,20 @@ func (s *StorageInvoiceMySQL) Create(i *Invoice) (err error) {
 	}
 
 	// query
-	query := "INSERT INTO invoices (`datetime`, total, customer_id) VALUES (?, ?, ?)"
+	query := "INSERT INTO invoices (id, `datetime`, total, customer_id) VALUES (?, ?, ?, ?)"
 
 	// prepare statement
 	var stmt *sql.Stmt
-	stmt, err = s.db.Prepare(query)
+	stmt, err = s.db.PrepareContext(ctx, query)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageInvoiceInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
 		return
 	}
 	defer stmt.Close()
 
 	// execute query
 	var result sql.Result
-	result, err = stmt.Exec(inMySQL.Datetime, inMySQL.Total, inMySQL.CustomerId)
+	result, err = stmt.ExecContext(ctx, inMySQL.Id, inMySQL.Datetime, inMySQL.Total, inMySQL.CustomerId)
 	if err != nil {
 		errMySQL, ok := err.(*mysql.MySQLError)
 		if ok {
@@ -118,12 +211,12 @@ func (s *StorageInvoiceMySQL) Create(i *Invoice) (err error) {
 			case 1452:
 				err = fmt.Errorf("%w. %v", ErrStorageInvoiceRelation, err)
 			default:
-				err = fmt.Errorf("%w. %v", ErrStorageInvoiceInternal, err)
+				err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
 			}
 			return
 		}
 
-		err = fmt.Errorf("%w. %v", ErrStorageInvoiceInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
 		return
 	}
 
@@ -131,7 +224,7 @@ func (s *StorageInvoiceMySQL) Create(i *Invoice) (err error) {
 	var rowsAffected int64
 	rowsAffected, err = result.RowsAffected()
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageInvoiceInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
 		return
 	}
 	if rowsAffected != 1 {
@@ -153,3 +246,134 @@ func (s *StorageInvoiceMySQL) Create(i *Invoice) (err error) {
 	return
 }
 
+// Update replaces the invoice with the same id
+func (s *StorageInvoiceMySQL) Update(ctx context.Context, i *Invoice) (err error) {
+	// deserialization
+	var inMySQL InvoiceMySQL
+	if i.Datetime != (Invoice{}).Datetime {
+		inMySQL.Datetime.Valid = true
+		inMySQL.Datetime.Time = i.Datetime
+	}
+	if i.Total != (Invoice{}).Total {
+		inMySQL.Total.Valid = true
+		inMySQL.Total.Float64 = i.Total
+	}
+	if i.CustomerId != (Invoice{}).CustomerId {
+		inMySQL.CustomerId.Valid = true
+		inMySQL.CustomerId.Int32 = int32(i.CustomerId)
+	}
+
+	// query
+	query := "UPDATE invoices SET `datetime` = ?, total = ?, customer_id = ? WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, inMySQL.Datetime, inMySQL.Total, inMySQL.CustomerId, i.Id)
+	if err != nil {
+		errMySQL, ok := err.(*mysql.MySQLError)
+		if ok {
+			switch errMySQL.Number {
+			case 1452:
+				err = fmt.Errorf("%w. %v", ErrStorageInvoiceRelation, err)
+			default:
+				err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+			}
+			return
+		}
+
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	// check rows affected (0 when the invoice does not exist or nothing changed)
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		_, err = s.ReadById(ctx, i.Id)
+		return
+	}
+
+	return
+}
+
+// Delete removes the invoice with the given id
+func (s *StorageInvoiceMySQL) Delete(ctx context.Context, id int) (err error) {
+	// query
+	query := "DELETE FROM invoices WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1451 {
+			err = fmt.Errorf("%w. %v", ErrStorageInvoiceReferenced, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		err = ErrStorageInvoiceNotFound
+		return
+	}
+
+	return
+}
+
+// UpdateTotals sets the total of every invoice to the sum of its sales (quantity * product price)
+func (s *StorageInvoiceMySQL) UpdateTotals(ctx context.Context) (err error) {
+	// query
+	query := "UPDATE invoices i SET i.total = (" +
+		"SELECT ROUND(COALESCE(SUM(sa.quantity * p.price), 0), 2) FROM sales sa " +
+		"INNER JOIN products p ON p.id = sa.product_id " +
+		"WHERE sa.invoice_id = i.id" +
+		")"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	_, err = stmt.ExecContext(ctx)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	return
+}
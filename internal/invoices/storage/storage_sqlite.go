@@ -0,0 +1,359 @@
+package storage
+
+import (
+	"app/internal/query"
+	"app/internal/sqlitedb"
+	"app/internal/sqltx"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// NewStorageInvoiceSQLite returns a new instance of StorageInvoiceSQLite
+func NewStorageInvoiceSQLite(db *sql.DB) *StorageInvoiceSQLite {
+	return &StorageInvoiceSQLite{db: db}
+}
+
+// InvoiceSQLite is a struct that represents a invoice in SQLite
+type InvoiceSQLite struct {
+	Id         sql.NullInt32
+	Datetime   sql.NullTime
+	Total      sql.NullFloat64
+	CustomerId sql.NullInt32
+}
+
+// StorageInvoiceSQLite is a struct that represents a invoice storage in SQLite for StorageInvoice interface
+type StorageInvoiceSQLite struct {
+	// db is the database or the transaction the operations run on
+	db sqltx.Executor
+}
+
+// InTx returns a copy of the storage whose operations run in the transaction tx (see sqltx.Runner)
+func (s *StorageInvoiceSQLite) InTx(tx *sql.Tx) *StorageInvoiceSQLite {
+	return &StorageInvoiceSQLite{db: tx}
+}
+
+// ReadAll returns all invoices
+func (s *StorageInvoiceSQLite) ReadAll(ctx context.Context) (is []*Invoice, err error) {
+	is, err = s.read(ctx, "SELECT id, `datetime`, total, customer_id FROM invoices")
+	return
+}
+
+// ReadPage returns the page of invoices of the query and the number of invoices that match its filters
+func (s *StorageInvoiceSQLite) ReadPage(ctx context.Context, q query.Query) (is []*Invoice, total int, err error) {
+	where, whereArgs := q.Where(invoiceColumns)
+	page, pageArgs := q.Page(invoiceColumns)
+
+	// count
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM invoices"+where, whereArgs...).Scan(&total)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	// page
+	is, err = s.read(ctx, "SELECT id, `datetime`, total, customer_id FROM invoices"+where+page, append(whereArgs, pageArgs...)...)
+	return
+}
+
+// read returns the invoices selected by the query with the args
+func (s *StorageInvoiceSQLite) read(ctx context.Context, query string, args ...any) (is []*Invoice, err error) {
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var rows *sql.Rows
+	rows, err = stmt.QueryContext(ctx, args...)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer rows.Close()
+
+	// iterate rows
+	for rows.Next() {
+		// scan row
+		var inSQLite InvoiceSQLite
+		err = rows.Scan(&inSQLite.Id, &inSQLite.Datetime, &inSQLite.Total, &inSQLite.CustomerId)
+		if err != nil {
+			err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+			return
+		}
+
+		// serialization
+		i := new(Invoice)
+		if inSQLite.Id.Valid {
+			i.Id = int(inSQLite.Id.Int32)
+		}
+		if inSQLite.Datetime.Valid {
+			i.Datetime = inSQLite.Datetime.Time
+		}
+		if inSQLite.Total.Valid {
+			i.Total = inSQLite.Total.Float64
+		}
+		if inSQLite.CustomerId.Valid {
+			i.CustomerId = int(inSQLite.CustomerId.Int32)
+		}
+
+		// append to slice
+		is = append(is, i)
+	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	return
+}
+
+// ReadById returns the invoice with the given id
+func (s *StorageInvoiceSQLite) ReadById(ctx context.Context, id int) (i *Invoice, err error) {
+	// query
+	query := "SELECT id, `datetime`, total, customer_id FROM invoices WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var inSQLite InvoiceSQLite
+	err = stmt.QueryRowContext(ctx, id).Scan(&inSQLite.Id, &inSQLite.Datetime, &inSQLite.Total, &inSQLite.CustomerId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w. %v", ErrStorageInvoiceNotFound, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	// serialization
+	i = new(Invoice)
+	if inSQLite.Id.Valid {
+		i.Id = int(inSQLite.Id.Int32)
+	}
+	if inSQLite.Datetime.Valid {
+		i.Datetime = inSQLite.Datetime.Time
+	}
+	if inSQLite.Total.Valid {
+		i.Total = inSQLite.Total.Float64
+	}
+	if inSQLite.CustomerId.Valid {
+		i.CustomerId = int(inSQLite.CustomerId.Int32)
+	}
+
+	return
+}
+
+// Create inserts a new invoice
+func (s *StorageInvoiceSQLite) Create(ctx context.Context, i *Invoice) (err error) {
+	// deserialization
+	var inSQLite InvoiceSQLite
+	if i.Id != (Invoice{}).Id {
+		inSQLite.Id.Valid = true
+		inSQLite.Id.Int32 = int32(i.Id)
+	}
+	if i.Datetime != (Invoice{}).Datetime {
+		inSQLite.Datetime.Valid = true
+		// in UTC, so the stored text compares in time order with the filter and cursor args (see query.Query.Where)
+		inSQLite.Datetime.Time = i.Datetime.UTC()
+	}
+	if i.Total != (Invoice{}).Total {
+		inSQLite.Total.Valid = true
+		inSQLite.Total.Float64 = i.Total
+	}
+	if i.CustomerId != (Invoice{}).CustomerId {
+		inSQLite.CustomerId.Valid = true
+		inSQLite.CustomerId.Int32 = int32(i.CustomerId)
+	}
+
+	// query
+	query := "INSERT INTO invoices (id, `datetime`, total, customer_id) VALUES (?, ?, ?, ?)"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, inSQLite.Id, inSQLite.Datetime, inSQLite.Total, inSQLite.CustomerId)
+	if err != nil {
+		if sqlitedb.IsForeignKeyError(err) {
+			err = fmt.Errorf("%w. %v", ErrStorageInvoiceRelation, err)
+			return
+		}
+
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	if rowsAffected != 1 {
+		err = fmt.Errorf("%w. %s", ErrStorageInvoiceInternal, "rows affected != 1")
+		return
+	}
+
+	// get last insert id
+	var lastInsertId int64
+	lastInsertId, err = result.LastInsertId()
+	if err != nil {
+		err = ErrStorageInvoiceInternal
+		return
+	}
+
+	// set id
+	i.Id = int(lastInsertId)
+
+	return
+}
+
+// Update replaces the invoice with the same id
+func (s *StorageInvoiceSQLite) Update(ctx context.Context, i *Invoice) (err error) {
+	// deserialization
+	var inSQLite InvoiceSQLite
+	if i.Datetime != (Invoice{}).Datetime {
+		inSQLite.Datetime.Valid = true
+		// in UTC, so the stored text compares in time order with the filter and cursor args (see query.Query.Where)
+		inSQLite.Datetime.Time = i.Datetime.UTC()
+	}
+	if i.Total != (Invoice{}).Total {
+		inSQLite.Total.Valid = true
+		inSQLite.Total.Float64 = i.Total
+	}
+	if i.CustomerId != (Invoice{}).CustomerId {
+		inSQLite.CustomerId.Valid = true
+		inSQLite.CustomerId.Int32 = int32(i.CustomerId)
+	}
+
+	// query
+	query := "UPDATE invoices SET `datetime` = ?, total = ?, customer_id = ? WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, inSQLite.Datetime, inSQLite.Total, inSQLite.CustomerId, i.Id)
+	if err != nil {
+		if sqlitedb.IsForeignKeyError(err) {
+			err = fmt.Errorf("%w. %v", ErrStorageInvoiceRelation, err)
+			return
+		}
+
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	// check rows affected (0 when the invoice does not exist or nothing changed)
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		_, err = s.ReadById(ctx, i.Id)
+		return
+	}
+
+	return
+}
+
+// Delete removes the invoice with the given id
+func (s *StorageInvoiceSQLite) Delete(ctx context.Context, id int) (err error) {
+	// query
+	query := "DELETE FROM invoices WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		if sqlitedb.IsForeignKeyError(err) {
+			err = fmt.Errorf("%w. %v", ErrStorageInvoiceReferenced, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		err = ErrStorageInvoiceNotFound
+		return
+	}
+
+	return
+}
+
+// UpdateTotals sets the total of every invoice to the sum of its sales (quantity * product price)
+func (s *StorageInvoiceSQLite) UpdateTotals(ctx context.Context) (err error) {
+	// query
+	query := "UPDATE invoices SET total = (" +
+		"SELECT ROUND(COALESCE(SUM(sa.quantity * p.price), 0), 2) FROM sales sa " +
+		"INNER JOIN products p ON p.id = sa.product_id " +
+		"WHERE sa.invoice_id = invoices.id" +
+		")"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	_, err = stmt.ExecContext(ctx)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageInvoiceInternal, err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"app/internal/query"
+	"app/internal/sqlitedb"
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StorageInvoiceSQLite
+func TestStorageInvoiceSQLite(t *testing.T) {
+	// arrange
+	db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("INSERT INTO customers (id) VALUES (1); INSERT INTO products (id, price) VALUES (1, 1.5), (2, 10)")
+	require.NoError(t, err)
+	st := NewStorageInvoiceSQLite(db)
+
+	t.Run("create with datetime and customer relation", func(t *testing.T) {
+		// act
+		dt := time.Date(2022, 5, 15, 23, 13, 56, 0, time.UTC)
+		inv := &Invoice{Datetime: dt, CustomerId: 1}
+		errOk := st.Create(context.Background(), inv)
+		read, errRead := st.ReadById(context.Background(), inv.Id)
+		errRelation := st.Create(context.Background(), &Invoice{Datetime: dt, CustomerId: 2})
+
+		// assert
+		require.NoError(t, errOk)
+		require.NoError(t, errRead)
+		require.True(t, dt.Equal(read.Datetime))
+		require.ErrorIs(t, errRelation, ErrStorageInvoiceRelation)
+	})
+
+	t.Run("update totals and delete restricted by sales", func(t *testing.T) {
+		// arrange
+		_, err := db.Exec("INSERT INTO sales (quantity, product_id, invoice_id) VALUES (3, 1, 1), (2, 2, 1)")
+		require.NoError(t, err)
+
+		// act
+		errTotals := st.UpdateTotals(context.Background())
+		read, _ := st.ReadById(context.Background(), 1)
+		errDelete := st.Delete(context.Background(), 1)
+
+		// assert
+		require.NoError(t, errTotals)
+		require.Equal(t, 24.5, read.Total)
+		require.ErrorIs(t, errDelete, ErrStorageInvoiceReferenced)
+	})
+}
+
+func TestStorageInvoiceSQLite_ReadPage(t *testing.T) {
+	// arrange
+	db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("INSERT INTO customers (id) VALUES (1), (2)")
+	require.NoError(t, err)
+	st := NewStorageInvoiceSQLite(db)
+	invoices := []*Invoice{
+		{Datetime: time.Date(2022, 2, 28, 23, 59, 59, 0, time.UTC), Total: 10, CustomerId: 1},
+		{Datetime: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), Total: 20, CustomerId: 1},
+		{Datetime: time.Date(2022, 3, 31, 23, 59, 59, 0, time.UTC), Total: 30, CustomerId: 1},
+		{Datetime: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC), Total: 40, CustomerId: 1},
+		{Datetime: time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC), Total: 50, CustomerId: 2},
+	}
+	for _, inv := range invoices {
+		require.NoError(t, st.Create(context.Background(), inv))
+	}
+	maxTotal := 25.0
+
+	// act
+	march := InvoiceFilter{CustomerId: 1, From: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)}
+	is, total, errMarch := st.ReadPage(context.Background(), query.Query{Filters: march.Filters()})
+	march.MaxTotal = &maxTotal
+	_, totalMax, errMax := st.ReadPage(context.Background(), query.Query{Filters: march.Filters()})
+
+	// assert
+	require.NoError(t, errMarch)
+	require.Equal(t, 2, total)
+	require.Equal(t, []int{2, 3}, []int{is[0].Id, is[1].Id})
+	require.NoError(t, errMax)
+	require.Equal(t, 1, totalMax)
+
+	t.Run("next cursor by datetime", func(t *testing.T) {
+		// arrange
+		q := query.Query{Sort: []query.Sort{{Field: "datetime", Desc: true}}, Limit: 3}
+		is, total, err := st.ReadPage(context.Background(), q)
+		require.NoError(t, err)
+		qNext, err := query.Parse(url.Values{"sort": {"-datetime"}, "limit": {"3"}, "after": {query.Next(q, total, is, InvoiceValue)}}, InvoiceFields)
+		require.NoError(t, err)
+
+		// act
+		is, _, err = st.ReadPage(context.Background(), qNext)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 1}, []int{is[0].Id, is[1].Id})
+	})
+
+	t.Run("non-UTC datetimes near a date boundary", func(t *testing.T) {
+		// arrange
+		april := &Invoice{Datetime: time.Date(2022, 3, 31, 22, 30, 0, 0, time.FixedZone("", -5*3600)), Total: 60, CustomerId: 2}
+		require.NoError(t, st.Create(context.Background(), april))
+		march := InvoiceFilter{CustomerId: 2, From: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)}
+		marchLocal := InvoiceFilter{CustomerId: 2, From: march.From.In(time.FixedZone("", 2*3600)), To: march.To.In(time.FixedZone("", 2*3600))}
+
+		// act
+		is, total, errMarch := st.ReadPage(context.Background(), query.Query{Filters: march.Filters()})
+		_, totalLocal, errLocal := st.ReadPage(context.Background(), query.Query{Filters: marchLocal.Filters()})
+		got, errRead := st.ReadById(context.Background(), april.Id)
+
+		// assert
+		require.NoError(t, errMarch)
+		require.Equal(t, 1, total)
+		require.Equal(t, 5, is[0].Id)
+		require.NoError(t, errLocal)
+		require.Equal(t, 1, totalLocal)
+		require.NoError(t, errRead)
+		require.True(t, april.Datetime.Equal(got.Datetime))
+	})
+}
@@ -0,0 +1,412 @@
+package jsondb
+
+import (
+	"app/internal/memdb"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DatetimeLayout is the layout of the invoice datetime in the json files
+const DatetimeLayout = "2006-01-02 15:04:05"
+
+// Canonical file names of each table (as in docs/db/json)
+const (
+	FileCustomers = "customers.json"
+	FileProducts  = "products.json"
+	FileInvoices  = "invoices.json"
+	FileSales     = "sales.json"
+	FileAPIKeys   = "api_keys.json"
+)
+
+// lockFile is the name of the lock file created in the directory of the json files
+const lockFile = ".jsondb.lock"
+
+var (
+	// ErrJSONDBFile is returned when a json file can not be read, decoded or written
+	ErrJSONDBFile = errors.New("jsondb file invalid")
+)
+
+// CustomerJSON is a customer in the json files
+type CustomerJSON struct {
+	Id        int    `json:"id"`
+	LastName  string `json:"last_name"`
+	FirstName string `json:"first_name"`
+	Condition bool   `json:"condition"`
+}
+
+// ProductJSON is a product in the json files
+type ProductJSON struct {
+	Id          int     `json:"id"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+}
+
+// InvoiceJSON is an invoice in the json files (datetime with DatetimeLayout, in UTC)
+type InvoiceJSON struct {
+	Id         int     `json:"id"`
+	Datetime   string  `json:"datetime"`
+	CustomerId int     `json:"customer_id"`
+	Total      float64 `json:"total"`
+}
+
+// SaleJSON is a sale in the json files
+type SaleJSON struct {
+	Id        int `json:"id"`
+	ProductId int `json:"product_id"`
+	InvoiceId int `json:"invoice_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// APIKeyJSON is an api key in the json files (datetimes with DatetimeLayout in UTC, revoked_at empty while it is active)
+type APIKeyJSON struct {
+	Id        int    `json:"id"`
+	Name      string `json:"name"`
+	KeyHash   string `json:"key_hash"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"created_at"`
+	RevokedAt string `json:"revoked_at"`
+}
+
+// Table identifies the file of a table
+type Table int
+
+const (
+	TableCustomers Table = iota
+	TableProducts
+	TableInvoices
+	TableSales
+	TableAPIKeys
+)
+
+// Files are the paths of the json file of each table
+type Files struct {
+	Customers string
+	Products  string
+	Invoices  string
+	Sales     string
+	APIKeys   string
+}
+
+// FilesFor returns the files of a data set where the file of table is path and
+// the other tables use their canonical names in the same directory
+func FilesFor(table Table, path string) (f Files) {
+	dir := filepath.Dir(path)
+	f = Files{
+		Customers: filepath.Join(dir, FileCustomers),
+		Products:  filepath.Join(dir, FileProducts),
+		Invoices:  filepath.Join(dir, FileInvoices),
+		Sales:     filepath.Join(dir, FileSales),
+		APIKeys:   filepath.Join(dir, FileAPIKeys),
+	}
+	switch table {
+	case TableCustomers:
+		f.Customers = path
+	case TableProducts:
+		f.Products = path
+	case TableInvoices:
+		f.Invoices = path
+	case TableSales:
+		f.Sales = path
+	case TableAPIKeys:
+		f.APIKeys = path
+	}
+	return
+}
+
+// New returns a new json file database
+func New(files Files) *DB {
+	return &DB{files: files}
+}
+
+// DB is a database persisted in json files. Every operation loads the files into an in-memory database,
+// holding a lock on the directory so concurrent writers (even from other processes) do not interleave
+type DB struct {
+	files Files
+}
+
+// View loads the files and runs fn holding a shared lock
+func (db *DB) View(fn func(mdb *memdb.DB) (err error)) (err error) {
+	var unlock func() error
+	unlock, err = lock(db.lockPath(), false)
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrJSONDBFile, err)
+		return
+	}
+	defer unlock()
+
+	var mdb *memdb.DB
+	mdb, err = db.load()
+	if err != nil {
+		return
+	}
+
+	err = fn(mdb)
+	return
+}
+
+// Update loads the files and runs fn holding an exclusive lock. If fn succeeds, the files of tables are saved
+func (db *DB) Update(fn func(mdb *memdb.DB) (err error), tables ...Table) (err error) {
+	var unlock func() error
+	unlock, err = lock(db.lockPath(), true)
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrJSONDBFile, err)
+		return
+	}
+	defer unlock()
+
+	var mdb *memdb.DB
+	mdb, err = db.load()
+	if err != nil {
+		return
+	}
+
+	err = fn(mdb)
+	if err != nil {
+		return
+	}
+
+	err = mdb.View(func(t *memdb.Tables) (err error) {
+		for _, table := range tables {
+			err = db.save(t, table)
+			if err != nil {
+				return
+			}
+		}
+		return
+	})
+	return
+}
+
+// lockPath returns the path of the lock file (in the directory of the invoices file, shared by the data set)
+func (db *DB) lockPath() string {
+	return filepath.Join(filepath.Dir(db.files.Invoices), lockFile)
+}
+
+// load reads the files into a new in-memory database (a missing file is an empty table)
+func (db *DB) load() (mdb *memdb.DB, err error) {
+	var (
+		cs []*CustomerJSON
+		ps []*ProductJSON
+		is []*InvoiceJSON
+		ss []*SaleJSON
+		ks []*APIKeyJSON
+	)
+	if err = readFile(db.files.Customers, &cs); err != nil {
+		return
+	}
+	if err = readFile(db.files.Products, &ps); err != nil {
+		return
+	}
+	if err = readFile(db.files.Invoices, &is); err != nil {
+		return
+	}
+	if err = readFile(db.files.Sales, &ss); err != nil {
+		return
+	}
+	if err = readFile(db.files.APIKeys, &ks); err != nil {
+		return
+	}
+
+	mdb = memdb.NewDB()
+	err = mdb.Update(func(t *memdb.Tables) (err error) {
+		for _, c := range cs {
+			id := t.CustomerId(c.Id)
+			t.Customers[id] = &memdb.CustomerRow{Id: id, FirstName: c.FirstName, LastName: c.LastName, Condition: c.Condition}
+		}
+		for _, p := range ps {
+			id := t.ProductId(p.Id)
+			t.Products[id] = &memdb.ProductRow{Id: id, Description: p.Description, Price: p.Price}
+		}
+		for _, i := range is {
+			var dt time.Time
+			dt, err = parseDatetime(i.Datetime)
+			if err != nil {
+				err = fmt.Errorf("%w. %s: invoice %d: %v", ErrJSONDBFile, db.files.Invoices, i.Id, err)
+				return
+			}
+			id := t.InvoiceId(i.Id)
+			t.Invoices[id] = &memdb.InvoiceRow{Id: id, Datetime: dt, Total: i.Total, CustomerId: i.CustomerId}
+		}
+		for _, s := range ss {
+			id := t.SaleId(s.Id)
+			t.Sales[id] = &memdb.SaleRow{Id: id, Quantity: s.Quantity, ProductId: s.ProductId, InvoiceId: s.InvoiceId}
+		}
+		for _, k := range ks {
+			row := &memdb.APIKeyRow{Name: k.Name, KeyHash: k.KeyHash, Role: k.Role}
+			row.CreatedAt, err = parseDatetime(k.CreatedAt)
+			if err == nil {
+				row.RevokedAt, err = parseDatetime(k.RevokedAt)
+			}
+			if err != nil {
+				err = fmt.Errorf("%w. %s: api key %d: %v", ErrJSONDBFile, db.files.APIKeys, k.Id, err)
+				return
+			}
+			row.Id = t.APIKeyId(k.Id)
+			t.APIKeys[row.Id] = row
+		}
+		return
+	})
+	if err != nil {
+		mdb = nil
+		return
+	}
+
+	return
+}
+
+// save writes the rows of a table to its file
+func (db *DB) save(t *memdb.Tables, table Table) (err error) {
+	switch table {
+	case TableCustomers:
+		rows := make([]*CustomerJSON, 0, len(t.Customers))
+		for _, c := range t.Customers {
+			rows = append(rows, &CustomerJSON{Id: c.Id, LastName: c.LastName, FirstName: c.FirstName, Condition: c.Condition})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Id < rows[j].Id })
+		err = writeFile(db.files.Customers, rows)
+	case TableProducts:
+		rows := make([]*ProductJSON, 0, len(t.Products))
+		for _, p := range t.Products {
+			rows = append(rows, &ProductJSON{Id: p.Id, Description: p.Description, Price: p.Price})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Id < rows[j].Id })
+		err = writeFile(db.files.Products, rows)
+	case TableInvoices:
+		rows := make([]*InvoiceJSON, 0, len(t.Invoices))
+		for _, i := range t.Invoices {
+			rows = append(rows, &InvoiceJSON{Id: i.Id, Datetime: formatDatetime(i.Datetime), CustomerId: i.CustomerId, Total: i.Total})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Id < rows[j].Id })
+		err = writeFile(db.files.Invoices, rows)
+	case TableSales:
+		rows := make([]*SaleJSON, 0, len(t.Sales))
+		for _, s := range t.Sales {
+			rows = append(rows, &SaleJSON{Id: s.Id, ProductId: s.ProductId, InvoiceId: s.InvoiceId, Quantity: s.Quantity})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Id < rows[j].Id })
+		err = writeFile(db.files.Sales, rows)
+	case TableAPIKeys:
+		rows := make([]*APIKeyJSON, 0, len(t.APIKeys))
+		for _, k := range t.APIKeys {
+			rows = append(rows, &APIKeyJSON{Id: k.Id, Name: k.Name, KeyHash: k.KeyHash, Role: k.Role, CreatedAt: formatDatetime(k.CreatedAt), RevokedAt: formatDatetime(k.RevokedAt)})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Id < rows[j].Id })
+		err = writeFile(db.files.APIKeys, rows)
+	}
+	return
+}
+
+// parseDatetime returns the time of a datetime with DatetimeLayout in UTC (zero if it is empty)
+func parseDatetime(s string) (t time.Time, err error) {
+	if s == "" {
+		return
+	}
+	t, err = time.Parse(DatetimeLayout, s)
+	return
+}
+
+// formatDatetime returns t with DatetimeLayout in UTC (empty if it is zero)
+func formatDatetime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(DatetimeLayout)
+}
+
+// ReadFile decodes the json array in the file at path into ptr
+func ReadFile(path string, ptr any) (err error) {
+	var b []byte
+	b, err = os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrJSONDBFile, err)
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(ptr)
+	if err != nil {
+		err = fmt.Errorf("%w. %s: %v", ErrJSONDBFile, path, err)
+		return
+	}
+	if dec.More() {
+		err = fmt.Errorf("%w. %s: unexpected data after the top-level array", ErrJSONDBFile, path)
+		return
+	}
+
+	return
+}
+
+// readFile is like ReadFile but a missing file leaves ptr untouched (an empty table)
+func readFile(path string, ptr any) (err error) {
+	if _, err = os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		err = nil
+		return
+	}
+
+	err = ReadFile(path, ptr)
+	return
+}
+
+// writeFile encodes rows as a json array (one object per line, like docs/db/json) and replaces the file
+// at path atomically: the data is written to a temporary file in the same directory that is then renamed
+func writeFile[T any](path string, rows []T) (err error) {
+	// encode
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, row := range rows {
+		var b []byte
+		b, err = json.Marshal(row)
+		if err != nil {
+			err = fmt.Errorf("%w. %s: %v", ErrJSONDBFile, path, err)
+			return
+		}
+		if i > 0 {
+			buf.WriteString(",\n")
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+
+	// write temporary file
+	var tmp *os.File
+	tmp, err = os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		err = fmt.Errorf("%w. %v", ErrJSONDBFile, err)
+		return
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err = tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		err = fmt.Errorf("%w. %v", ErrJSONDBFile, err)
+		return
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		err = fmt.Errorf("%w. %v", ErrJSONDBFile, err)
+		return
+	}
+	if err = tmp.Close(); err != nil {
+		err = fmt.Errorf("%w. %v", ErrJSONDBFile, err)
+		return
+	}
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		err = fmt.Errorf("%w. %v", ErrJSONDBFile, err)
+		return
+	}
+
+	// replace file
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		err = fmt.Errorf("%w. %v", ErrJSONDBFile, err)
+		return
+	}
+
+	return
+}
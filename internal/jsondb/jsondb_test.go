@@ -0,0 +1,146 @@
+package jsondb
+
+import (
+	"app/internal/memdb"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// copyDocs copies the json files of docs/db/json to a temporary directory
+func copyDocs(t *testing.T) (dir string) {
+	dir = t.TempDir()
+	for _, name := range []string{FileCustomers, FileProducts, FileInvoices, FileSales} {
+		b, err := os.ReadFile(filepath.Join("../../docs/db/json", name))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), b, 0o644))
+	}
+	return
+}
+
+// Tests for DB
+func TestDB_View(t *testing.T) {
+	t.Run("loads the docs data set", func(t *testing.T) {
+		// arrange
+		dir := copyDocs(t)
+		db := New(FilesFor(TableSales, filepath.Join(dir, FileSales)))
+
+		// act
+		var customers, invoices, sales int
+		err := db.View(func(mdb *memdb.DB) (err error) {
+			return mdb.View(func(t *memdb.Tables) (err error) {
+				customers, invoices, sales = len(t.Customers), len(t.Invoices), len(t.Sales)
+				return
+			})
+		})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 100, customers)
+		require.Equal(t, 100, invoices)
+		require.Equal(t, 1000, sales)
+	})
+
+	t.Run("missing files are empty tables", func(t *testing.T) {
+		// arrange
+		db := New(FilesFor(TableProducts, filepath.Join(t.TempDir(), FileProducts)))
+
+		// act
+		var products int
+		err := db.View(func(mdb *memdb.DB) (err error) {
+			return mdb.View(func(t *memdb.Tables) (err error) {
+				products = len(t.Products)
+				return
+			})
+		})
+
+		// assert
+		require.NoError(t, err)
+		require.Zero(t, products)
+	})
+}
+
+func TestDB_Update(t *testing.T) {
+	t.Run("saves only the given tables and keeps the file format", func(t *testing.T) {
+		// arrange
+		dir := copyDocs(t)
+		db := New(FilesFor(TableCustomers, filepath.Join(dir, FileCustomers)))
+		before, err := os.ReadFile(filepath.Join(dir, FileInvoices))
+		require.NoError(t, err)
+
+		// act
+		err = db.Update(func(mdb *memdb.DB) (err error) {
+			return mdb.Update(func(t *memdb.Tables) (err error) {
+				t.Customers[1].FirstName = "Changed"
+				t.Invoices[1].Total = 99
+				return
+			})
+		}, TableCustomers)
+
+		// assert
+		require.NoError(t, err)
+		after, err := os.ReadFile(filepath.Join(dir, FileInvoices))
+		require.NoError(t, err)
+		require.Equal(t, before, after)
+		var cs []*CustomerJSON
+		require.NoError(t, ReadFile(filepath.Join(dir, FileCustomers), &cs))
+		require.Len(t, cs, 100)
+		require.Equal(t, "Changed", cs[0].FirstName)
+		matches, err := filepath.Glob(filepath.Join(dir, "*.tmp-*"))
+		require.NoError(t, err)
+		require.Empty(t, matches)
+	})
+
+	t.Run("datetimes are saved in UTC", func(t *testing.T) {
+		// arrange
+		db := New(FilesFor(TableInvoices, filepath.Join(t.TempDir(), FileInvoices)))
+		dt := time.Date(2024, 3, 31, 22, 30, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+
+		// act
+		errSave := db.Update(func(mdb *memdb.DB) (err error) {
+			return mdb.Update(func(t *memdb.Tables) (err error) {
+				t.Invoices[1] = &memdb.InvoiceRow{Id: 1, Datetime: dt}
+				return
+			})
+		}, TableInvoices)
+		var read time.Time
+		errRead := db.View(func(mdb *memdb.DB) (err error) {
+			return mdb.View(func(t *memdb.Tables) (err error) {
+				read = t.Invoices[1].Datetime
+				return
+			})
+		})
+
+		// assert
+		require.NoError(t, errSave)
+		require.NoError(t, errRead)
+		require.Equal(t, time.Date(2024, 4, 1, 3, 30, 0, 0, time.UTC), read)
+		require.True(t, dt.Equal(read))
+	})
+
+	t.Run("nothing is saved when fn fails", func(t *testing.T) {
+		// arrange
+		dir := copyDocs(t)
+		db := New(FilesFor(TableCustomers, filepath.Join(dir, FileCustomers)))
+		before, err := os.ReadFile(filepath.Join(dir, FileCustomers))
+		require.NoError(t, err)
+
+		// act
+		err = db.Update(func(mdb *memdb.DB) (err error) {
+			_ = mdb.Update(func(t *memdb.Tables) (err error) {
+				delete(t.Customers, 1)
+				return
+			})
+			return os.ErrInvalid
+		}, TableCustomers)
+
+		// assert
+		require.ErrorIs(t, err, os.ErrInvalid)
+		after, err := os.ReadFile(filepath.Join(dir, FileCustomers))
+		require.NoError(t, err)
+		require.Equal(t, before, after)
+	})
+}
@@ -0,0 +1,31 @@
+//go:build !unix
+
+package jsondb
+
+import "sync"
+
+// locks are the in-process locks by path (advisory file locks are only implemented on unix)
+var (
+	locksMu sync.Mutex
+	locks   = make(map[string]*sync.RWMutex)
+)
+
+// lock acquires an in-process lock for path, exclusive for writers and shared for readers
+func lock(path string, exclusive bool) (unlock func() error, err error) {
+	locksMu.Lock()
+	mu, ok := locks[path]
+	if !ok {
+		mu = new(sync.RWMutex)
+		locks[path] = mu
+	}
+	locksMu.Unlock()
+
+	if exclusive {
+		mu.Lock()
+		unlock = func() error { mu.Unlock(); return nil }
+		return
+	}
+	mu.RLock()
+	unlock = func() error { mu.RUnlock(); return nil }
+	return
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package jsondb
+
+import (
+	"os"
+	"syscall"
+)
+
+// lock acquires an advisory lock (flock) on the file at path, creating it if needed.
+// The lock is exclusive for writers and shared for readers
+func lock(path string, exclusive bool) (unlock func() error, err error) {
+	var f *os.File
+	f, err = os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return
+	}
+
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	err = syscall.Flock(int(f.Fd()), how)
+	if err != nil {
+		f.Close()
+		return
+	}
+
+	unlock = func() error {
+		// closing the file releases the lock
+		return f.Close()
+	}
+	return
+}
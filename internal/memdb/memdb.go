@@ -0,0 +1,138 @@
+package memdb
+
+import (
+	"sync"
+	"time"
+)
+
+// CustomerRow is a row of the customers table
+type CustomerRow struct {
+	Id        int
+	FirstName string
+	LastName  string
+	Condition bool
+}
+
+// ProductRow is a row of the products table
+type ProductRow struct {
+	Id          int
+	Description string
+	Price       float64
+}
+
+// InvoiceRow is a row of the invoices table
+type InvoiceRow struct {
+	Id         int
+	Datetime   time.Time
+	Total      float64
+	CustomerId int
+}
+
+// SaleRow is a row of the sales table
+type SaleRow struct {
+	Id        int
+	Quantity  int
+	ProductId int
+	InvoiceId int
+}
+
+// APIKeyRow is a row of the api_keys table (RevokedAt is zero while the key is active)
+type APIKeyRow struct {
+	Id        int
+	Name      string
+	KeyHash   string
+	Role      string
+	CreatedAt time.Time
+	RevokedAt time.Time
+}
+
+// Tables are the tables of the in-memory database (the same as docs/db/mysql/database.sql)
+type Tables struct {
+	Customers map[int]*CustomerRow
+	Products  map[int]*ProductRow
+	Invoices  map[int]*InvoiceRow
+	Sales     map[int]*SaleRow
+	APIKeys   map[int]*APIKeyRow
+
+	// auto increment counters
+	lastCustomerId int
+	lastProductId  int
+	lastInvoiceId  int
+	lastSaleId     int
+	lastAPIKeyId   int
+}
+
+// NewDB returns a new empty in-memory database
+func NewDB() *DB {
+	return &DB{
+		t: &Tables{
+			Customers: make(map[int]*CustomerRow),
+			Products:  make(map[int]*ProductRow),
+			Invoices:  make(map[int]*InvoiceRow),
+			Sales:     make(map[int]*SaleRow),
+			APIKeys:   make(map[int]*APIKeyRow),
+		},
+	}
+}
+
+// DB is an in-memory database shared by the map storages, safe for concurrent use.
+// Every operation runs with the whole database locked, so cross-table checks (such as foreign keys) are consistent
+type DB struct {
+	mu sync.RWMutex
+	t  *Tables
+}
+
+// View runs fn with the database locked for reading
+func (db *DB) View(fn func(t *Tables) (err error)) (err error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	err = fn(db.t)
+	return
+}
+
+// Update runs fn with the database locked for writing
+func (db *DB) Update(fn func(t *Tables) (err error)) (err error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	err = fn(db.t)
+	return
+}
+
+// CustomerId returns the id for a new customer: id if it is set, otherwise the next auto increment value
+func (t *Tables) CustomerId(id int) int {
+	return autoIncrement(&t.lastCustomerId, id)
+}
+
+// ProductId returns the id for a new product: id if it is set, otherwise the next auto increment value
+func (t *Tables) ProductId(id int) int {
+	return autoIncrement(&t.lastProductId, id)
+}
+
+// InvoiceId returns the id for a new invoice: id if it is set, otherwise the next auto increment value
+func (t *Tables) InvoiceId(id int) int {
+	return autoIncrement(&t.lastInvoiceId, id)
+}
+
+// SaleId returns the id for a new sale: id if it is set, otherwise the next auto increment value
+func (t *Tables) SaleId(id int) int {
+	return autoIncrement(&t.lastSaleId, id)
+}
+
+// APIKeyId returns the id for a new api key: id if it is set, otherwise the next auto increment value
+func (t *Tables) APIKeyId(id int) int {
+	return autoIncrement(&t.lastAPIKeyId, id)
+}
+
+// autoIncrement works like a MySQL AUTO_INCREMENT column: an explicit id moves the counter forward
+func autoIncrement(last *int, id int) int {
+	if id == 0 {
+		*last++
+		return *last
+	}
+	if id > *last {
+		*last = id
+	}
+	return id
+}
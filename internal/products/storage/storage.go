@@ -1,7 +1,11 @@
 // storage.go
 package storage
 
-import "errors"
+import (
+	"app/internal/query"
+	"context"
+	"errors"
+)
 
 // Product is a struct that represents a product
 type Product struct {
@@ -10,13 +14,55 @@ type Product struct {
 	Price       float64
 }
 
+// ProductTopSold is a struct that represents a product with the total quantity sold
+type ProductTopSold struct {
+	Id          int
+	Description string
+	Total       int
+}
+
+// ProductFields are the fields of a product that ReadPage can filter and sort by
+var ProductFields = query.Fields{
+	"id":          query.KindInt,
+	"description": query.KindString,
+	"price":       query.KindFloat,
+}
+
+// ProductValue returns the value of a field of ProductFields of the product (the value of its column in the sql storages)
+func ProductValue(p *Product, field string) any {
+	switch field {
+	case "id":
+		return p.Id
+	case "description":
+		return p.Description
+	case "price":
+		return p.Price
+	}
+	return nil
+}
+
 // StorageProduct is an interface that represents a product storage
 type StorageProduct interface {
 	// ReadAll returns all products
-	ReadAll() (ps []*Product, err error)
+	ReadAll(ctx context.Context) (ps []*Product, err error)
+
+	// ReadPage returns the page of products of the query and the number of products that match its filters
+	ReadPage(ctx context.Context, q query.Query) (ps []*Product, total int, err error)
+
+	// ReadById returns the product with the given id
+	ReadById(ctx context.Context, id int) (p *Product, err error)
+
+	// Create inserts a new product (the id is kept if set, otherwise it is generated)
+	Create(ctx context.Context, p *Product) (err error)
+
+	// Update replaces the product with the same id
+	Update(ctx context.Context, p *Product) (err error)
+
+	// Delete removes the product with the given id
+	Delete(ctx context.Context, id int) (err error)
 
-	// Create inserts a new product
-	Create(p *Product) (err error)
+	// ReadTopSold returns the limit products with the highest total quantity sold
+	ReadTopSold(ctx context.Context, limit int) (ps []*ProductTopSold, err error)
 }
 
 var (
@@ -24,4 +70,6 @@ var (
 	ErrStorageProductInternal = errors.New("internal storage error")
 	// ErrStorageProductNotFound is returned when a product is not found
 	ErrStorageProductNotFound = errors.New("product not found")
+	// ErrStorageProductReferenced is returned when a product can not be deleted because sales reference it
+	ErrStorageProductReferenced = errors.New("product referenced by sales")
 )
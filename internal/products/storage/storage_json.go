@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"app/internal/jsondb"
+	"app/internal/memdb"
+	"app/internal/query"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// NewStorageProductJSON returns a new instance of StorageProductJSON for the json file at path.
+// The related tables are read from the files with their canonical names in the same directory (see jsondb.FilesFor)
+func NewStorageProductJSON(path string) *StorageProductJSON {
+	return &StorageProductJSON{db: jsondb.New(jsondb.FilesFor(jsondb.TableProducts, path))}
+}
+
+// StorageProductJSON is a struct that represents a product storage in a json file for StorageProduct interface.
+// Each operation works on the data loaded from the files, with the same semantics as StorageProductMap
+type StorageProductJSON struct {
+	db *jsondb.DB
+}
+
+// ReadAll returns all products
+func (s *StorageProductJSON) ReadAll(ctx context.Context) (ps []*Product, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		ps, err = NewStorageProductMap(mdb).ReadAll(ctx)
+		return
+	})
+	err = productJSONError(err)
+	return
+}
+
+// ReadPage returns the page of products of the query and the number of products that match its filters
+func (s *StorageProductJSON) ReadPage(ctx context.Context, q query.Query) (ps []*Product, total int, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		ps, total, err = NewStorageProductMap(mdb).ReadPage(ctx, q)
+		return
+	})
+	err = productJSONError(err)
+	return
+}
+
+// ReadById returns the product with the given id
+func (s *StorageProductJSON) ReadById(ctx context.Context, id int) (p *Product, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		p, err = NewStorageProductMap(mdb).ReadById(ctx, id)
+		return
+	})
+	err = productJSONError(err)
+	return
+}
+
+// Create inserts a new product (the id is kept if set, otherwise it is generated)
+func (s *StorageProductJSON) Create(ctx context.Context, p *Product) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageProductMap(mdb).Create(ctx, p)
+		return
+	}, jsondb.TableProducts)
+	err = productJSONError(err)
+	return
+}
+
+// Update replaces the product with the same id
+func (s *StorageProductJSON) Update(ctx context.Context, p *Product) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageProductMap(mdb).Update(ctx, p)
+		return
+	}, jsondb.TableProducts)
+	err = productJSONError(err)
+	return
+}
+
+// Delete removes the product with the given id
+func (s *StorageProductJSON) Delete(ctx context.Context, id int) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageProductMap(mdb).Delete(ctx, id)
+		return
+	}, jsondb.TableProducts)
+	err = productJSONError(err)
+	return
+}
+
+// ReadTopSold returns the limit products with the highest total quantity sold
+func (s *StorageProductJSON) ReadTopSold(ctx context.Context, limit int) (ps []*ProductTopSold, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		ps, err = NewStorageProductMap(mdb).ReadTopSold(ctx, limit)
+		return
+	})
+	err = productJSONError(err)
+	return
+}
+
+// productJSONError wraps the errors of the json files as internal storage errors
+func productJSONError(err error) error {
+	if errors.Is(err, jsondb.ErrJSONDBFile) {
+		return fmt.Errorf("%w. %v", ErrStorageProductInternal, err)
+	}
+	return err
+}
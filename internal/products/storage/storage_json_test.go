@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StorageProductJSON
+func TestStorageProductJSON_Create(t *testing.T) {
+	t.Run("concurrent writers", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "products.json")
+		n := 20
+
+		// act
+		var wg sync.WaitGroup
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// each writer uses its own storage, as different processes would
+				errs <- NewStorageProductJSON(path).Create(context.Background(), &Product{Description: "Beans", Price: 12.89})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		// assert
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		ps, err := NewStorageProductJSON(path).ReadAll(context.Background())
+		require.NoError(t, err)
+		require.Len(t, ps, n)
+		for i, p := range ps {
+			require.Equal(t, i+1, p.Id)
+		}
+	})
+}
+
+func TestStorageProductJSON_ReadById(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		// arrange
+		st := NewStorageProductJSON(filepath.Join(t.TempDir(), "products.json"))
+		require.NoError(t, st.Create(context.Background(), &Product{Id: 7, Description: "Beans", Price: 12.89}))
+
+		// act
+		p, err := st.ReadById(context.Background(), 7)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, &Product{Id: 7, Description: "Beans", Price: 12.89}, p)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// arrange
+		st := NewStorageProductJSON(filepath.Join(t.TempDir(), "products.json"))
+
+		// act
+		p, err := st.ReadById(context.Background(), 1)
+
+		// assert
+		require.Nil(t, p)
+		require.ErrorIs(t, err, ErrStorageProductNotFound)
+	})
+}
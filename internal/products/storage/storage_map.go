@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"app/internal/memdb"
+	"app/internal/query"
+	"context"
+	"fmt"
+	"sort"
+)
+
+// NewStorageProductMap returns a new instance of StorageProductMap
+func NewStorageProductMap(db *memdb.DB) *StorageProductMap {
+	return &StorageProductMap{db}
+}
+
+// StorageProductMap is a struct that represents a product storage in memory for StorageProduct interface
+type StorageProductMap struct {
+	db *memdb.DB
+}
+
+// ReadAll returns all products
+func (s *StorageProductMap) ReadAll(ctx context.Context) (ps []*Product, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		for _, row := range t.Products {
+			ps = append(ps, productFromRow(row))
+		}
+		return
+	})
+
+	// sort by id (primary key order)
+	sort.Slice(ps, func(i, j int) bool { return ps[i].Id < ps[j].Id })
+	return
+}
+
+// ReadPage returns the page of products of the query and the number of products that match its filters
+func (s *StorageProductMap) ReadPage(ctx context.Context, q query.Query) (ps []*Product, total int, err error) {
+	ps, err = s.ReadAll(ctx)
+	if err != nil {
+		return
+	}
+
+	ps, total = query.Apply(ps, q, ProductValue)
+	return
+}
+
+// ReadById returns the product with the given id
+func (s *StorageProductMap) ReadById(ctx context.Context, id int) (p *Product, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		row, ok := t.Products[id]
+		if !ok {
+			err = ErrStorageProductNotFound
+			return
+		}
+
+		p = productFromRow(row)
+		return
+	})
+	return
+}
+
+// Create inserts a new product (the id is kept if set, otherwise it is generated)
+func (s *StorageProductMap) Create(ctx context.Context, p *Product) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		// check duplicated id
+		if _, ok := t.Products[p.Id]; ok {
+			err = fmt.Errorf("%w. duplicated id %d", ErrStorageProductInternal, p.Id)
+			return
+		}
+
+		// insert
+		p.Id = t.ProductId(p.Id)
+		t.Products[p.Id] = productToRow(p)
+		return
+	})
+	return
+}
+
+// Update replaces the product with the same id
+func (s *StorageProductMap) Update(ctx context.Context, p *Product) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		if _, ok := t.Products[p.Id]; !ok {
+			err = ErrStorageProductNotFound
+			return
+		}
+
+		t.Products[p.Id] = productToRow(p)
+		return
+	})
+	return
+}
+
+// Delete removes the product with the given id
+func (s *StorageProductMap) Delete(ctx context.Context, id int) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		if _, ok := t.Products[id]; !ok {
+			err = ErrStorageProductNotFound
+			return
+		}
+
+		// restrict: sales reference the product
+		for _, sa := range t.Sales {
+			if sa.ProductId == id {
+				err = fmt.Errorf("%w. sale %d", ErrStorageProductReferenced, sa.Id)
+				return
+			}
+		}
+
+		delete(t.Products, id)
+		return
+	})
+	return
+}
+
+// ReadTopSold returns the limit products with the highest total quantity sold
+func (s *StorageProductMap) ReadTopSold(ctx context.Context, limit int) (ps []*ProductTopSold, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		// group (only products with sales)
+		totals := make(map[int]int)
+		for _, sa := range t.Sales {
+			if _, ok := t.Products[sa.ProductId]; !ok {
+				continue
+			}
+			totals[sa.ProductId] += sa.Quantity
+		}
+
+		for id, total := range totals {
+			ps = append(ps, &ProductTopSold{Id: id, Description: t.Products[id].Description, Total: total})
+		}
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	// rank
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Total != ps[j].Total {
+			return ps[i].Total > ps[j].Total
+		}
+		return ps[i].Id < ps[j].Id
+	})
+	if len(ps) > limit {
+		ps = ps[:limit]
+	}
+	return
+}
+
+// productFromRow returns a product with the values of the row
+func productFromRow(row *memdb.ProductRow) *Product {
+	return &Product{
+		Id:          row.Id,
+		Description: row.Description,
+		Price:       row.Price,
+	}
+}
+
+// productToRow returns a row with the values of the product
+func productToRow(p *Product) *memdb.ProductRow {
+	return &memdb.ProductRow{
+		Id:          p.Id,
+		Description: p.Description,
+		Price:       p.Price,
+	}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"app/internal/memdb"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StorageProductMap
+func TestStorageProductMap_ReadTopSold(t *testing.T) {
+	t.Run("ranked by quantity sold", func(t *testing.T) {
+		// arrange
+		db := memdb.NewDB()
+		_ = db.Update(func(t *memdb.Tables) (err error) {
+			t.Products[1] = &memdb.ProductRow{Id: 1, Description: "Beans"}
+			t.Products[2] = &memdb.ProductRow{Id: 2, Description: "Juice"}
+			t.Products[3] = &memdb.ProductRow{Id: 3, Description: "Pastry"}
+			t.Sales[1] = &memdb.SaleRow{Id: 1, ProductId: 1, Quantity: 3}
+			t.Sales[2] = &memdb.SaleRow{Id: 2, ProductId: 2, Quantity: 5}
+			t.Sales[3] = &memdb.SaleRow{Id: 3, ProductId: 1, Quantity: 4}
+			return
+		})
+		st := NewStorageProductMap(db)
+
+		// act
+		ps, err := st.ReadTopSold(context.Background(), 5)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []*ProductTopSold{
+			{Id: 1, Description: "Beans", Total: 7},
+			{Id: 2, Description: "Juice", Total: 5},
+		}, ps)
+	})
+}
+
+func TestStorageProductMap_Delete(t *testing.T) {
+	t.Run("restricted by sales", func(t *testing.T) {
+		// arrange
+		db := memdb.NewDB()
+		st := NewStorageProductMap(db)
+		p := &Product{Description: "Beans", Price: 12.89}
+		require.NoError(t, st.Create(context.Background(), p))
+		_ = db.Update(func(t *memdb.Tables) (err error) {
+			t.Sales[1] = &memdb.SaleRow{Id: 1, ProductId: p.Id}
+			return
+		})
+
+		// act
+		err := st.Delete(context.Background(), p.Id)
+
+		// assert
+		require.ErrorIs(t, err, ErrStorageProductReferenced)
+	})
+}
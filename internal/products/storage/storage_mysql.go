@@ -1,8 +1,14 @@
 package storage
 
 import (
+	"app/internal/query"
+	"app/internal/sqltx"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 // NewStorageProductMySQL returns a new instance of StorageProductMySQL
@@ -17,32 +23,67 @@ type ProductMySQL struct {
 	Price       sql.NullFloat64
 }
 
+// productColumns are the columns (or expressions) of the ProductFields, also used by StorageProductSQLite.
+// Null values are read as zero values, as in the serialization of the rows
+var productColumns = map[string]string{
+	"id":          "id",
+	"description": "COALESCE(`description`, '')",
+	"price":       "COALESCE(price, 0)",
+}
+
 // StorageProductMySQL is a struct that represents a product storage in MySQL for StorageProduct interface
 type StorageProductMySQL struct {
-	db *sql.DB
+	// db is the database or the transaction the operations run on
+	db sqltx.Executor
+}
+
+// InTx returns a copy of the storage whose operations run in the transaction tx (see sqltx.Runner)
+func (s *StorageProductMySQL) InTx(tx *sql.Tx) *StorageProductMySQL {
+	return &StorageProductMySQL{db: tx}
 }
 
 // ReadAll returns all products
-func (s *StorageProductMySQL) ReadAll() (ps []*Product, err error) {
-	// query
-	query := "SELECT id, `description`, price FROM products"
+func (s *StorageProductMySQL) ReadAll(ctx context.Context) (ps []*Product, err error) {
+	ps, err = s.read(ctx, "SELECT id, `description`, price FROM products")
+	return
+}
+
+// ReadPage returns the page of products of the query and the number of products that match its filters
+func (s *StorageProductMySQL) ReadPage(ctx context.Context, q query.Query) (ps []*Product, total int, err error) {
+	where, whereArgs := q.Where(productColumns)
+	page, pageArgs := q.Page(productColumns)
 
+	// count
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products"+where, whereArgs...).Scan(&total)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	// page
+	ps, err = s.read(ctx, "SELECT id, `description`, price FROM products"+where+page, append(whereArgs, pageArgs...)...)
+	return
+}
+
+// read returns the products selected by the query with the args
+func (s *StorageProductMySQL) read(ctx context.Context, query string, args ...any) (ps []*Product, err error) {
 	// prepare statement
 	var stmt *sql.Stmt
-	stmt, err = s.db.Prepare(query)
+	stmt, err = s.db.PrepareContext(ctx, query)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageProductInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
 		return
 	}
 	defer stmt.Close()
 
 	// execute query
 	var rows *sql.Rows
-	rows, err = stmt.Query()
+	rows, err = stmt.QueryContext(ctx, args...)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageProductInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
 		return
 	}
+	defer rows.Close()
 
 	// iterate rows
 	for rows.Next() {
@@ -50,7 +91,7 @@ func (s *StorageProductMySQL) ReadAll() (ps []*Product, err error) {
 		var psMySQL ProductMySQL
 		err = rows.Scan(&psMySQL.Id, &psMySQL.Description, &psMySQL.Price)
 		if err != nil {
-			err = fmt.Errorf("%w. %v", ErrStorageProductInternal, err)
+			err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
 			return
 		}
 
@@ -69,14 +110,64 @@ func (s *StorageProductMySQL) ReadAll() (ps []*Product, err error) {
 		// append to list
 		ps = append(ps, p)
 	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	return
+}
+
+// ReadById returns the product with the given id
+func (s *StorageProductMySQL) ReadById(ctx context.Context, id int) (p *Product, err error) {
+	// query
+	query := "SELECT id, `description`, price FROM products WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var psMySQL ProductMySQL
+	err = stmt.QueryRowContext(ctx, id).Scan(&psMySQL.Id, &psMySQL.Description, &psMySQL.Price)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w. %v", ErrStorageProductNotFound, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	// serialization
+	p = new(Product)
+	if psMySQL.Id.Valid {
+		p.Id = int(psMySQL.Id.Int32)
+	}
+	if psMySQL.Description.Valid {
+		p.Description = psMySQL.Description.String
+	}
+	if psMySQL.Price.Valid {
+		p.Price = psMySQL.Price.Float64
+	}
 
 	return
 }
 
 // Create inserts a new product
-func (s *StorageProductMySQL) Create(p *Product) (err error) {
+func (s *StorageProductMySQL) Create(ctx context.Context, p *Product) (err error) {
 	// deserialization
 	var psMySQL ProductMySQL
+	if p.Id != 0 {
+		psMySQL.Id.Valid = true
+		psMySQL.Id.Int32 = int32(p.Id)
+	}
 	if p.Description != "" {
 		psMySQL.Description.Valid = true
 		psMySQL.Description.String = p.Description
@@ -87,22 +178,22 @@ func (s *StorageProductMySQL) Create(p *Product) (err error) {
 	}
 
 	// query
-	query := "INSERT INTO products (`description`, price) VALUES (?, ?)"
+	query := "INSERT INTO products (id, `description`, price) VALUES (?, ?, ?)"
 
 	// prepare statement
 	var stmt *sql.Stmt
-	stmt, err = s.db.Prepare(query)
+	stmt, err = s.db.PrepareContext(ctx, query)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageProductInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
 		return
 	}
 	defer stmt.Close()
 
 	// execute query
 	var res sql.Result
-	res, err = stmt.Exec(psMySQL.Description, psMySQL.Price)
+	res, err = stmt.ExecContext(ctx, psMySQL.Id, psMySQL.Description, psMySQL.Price)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageProductInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
 		return
 	}
 
@@ -110,7 +201,7 @@ func (s *StorageProductMySQL) Create(p *Product) (err error) {
 	var rowsAffected int64
 	rowsAffected, err = res.RowsAffected()
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageProductInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
 		return
 	}
 	if rowsAffected != 1 {
@@ -122,7 +213,7 @@ func (s *StorageProductMySQL) Create(p *Product) (err error) {
 	var id int64
 	id, err = res.LastInsertId()
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageProductInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
 		return
 	}
 
@@ -130,4 +221,144 @@ func (s *StorageProductMySQL) Create(p *Product) (err error) {
 	p.Id = int(id)
 
 	return
-}
\ No newline at end of file
+}
+
+// Update replaces the product with the same id
+func (s *StorageProductMySQL) Update(ctx context.Context, p *Product) (err error) {
+	// deserialization
+	var psMySQL ProductMySQL
+	if p.Description != "" {
+		psMySQL.Description.Valid = true
+		psMySQL.Description.String = p.Description
+	}
+	if p.Price != 0 {
+		psMySQL.Price.Valid = true
+		psMySQL.Price.Float64 = p.Price
+	}
+
+	// query
+	query := "UPDATE products SET `description` = ?, price = ? WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, psMySQL.Description, psMySQL.Price, p.Id)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	// check rows affected (0 when the product does not exist or nothing changed)
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		_, err = s.ReadById(ctx, p.Id)
+		return
+	}
+
+	return
+}
+
+// Delete removes the product with the given id
+func (s *StorageProductMySQL) Delete(ctx context.Context, id int) (err error) {
+	// query
+	query := "DELETE FROM products WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1451 {
+			err = fmt.Errorf("%w. %v", ErrStorageProductReferenced, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		err = ErrStorageProductNotFound
+		return
+	}
+
+	return
+}
+
+// ReadTopSold returns the limit products with the highest total quantity sold
+func (s *StorageProductMySQL) ReadTopSold(ctx context.Context, limit int) (ps []*ProductTopSold, err error) {
+	// query
+	query := "SELECT p.id, p.`description`, COALESCE(SUM(sa.quantity), 0) AS total " +
+		"FROM products p INNER JOIN sales sa ON sa.product_id = p.id " +
+		"GROUP BY p.id, p.`description` ORDER BY total DESC, p.id LIMIT ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var rows *sql.Rows
+	rows, err = stmt.QueryContext(ctx, limit)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer rows.Close()
+
+	// iterate rows
+	for rows.Next() {
+		// scan row
+		var description sql.NullString
+		p := new(ProductTopSold)
+		err = rows.Scan(&p.Id, &description, &p.Total)
+		if err != nil {
+			err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+			return
+		}
+		if description.Valid {
+			p.Description = description.String
+		}
+
+		// append to list
+		ps = append(ps, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	return
+}
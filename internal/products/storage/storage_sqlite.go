@@ -0,0 +1,355 @@
+package storage
+
+import (
+	"app/internal/query"
+	"app/internal/sqlitedb"
+	"app/internal/sqltx"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// NewStorageProductSQLite returns a new instance of StorageProductSQLite
+func NewStorageProductSQLite(db *sql.DB) *StorageProductSQLite {
+	return &StorageProductSQLite{db}
+}
+
+// ProductSQLite is a struct that represents a product in SQLite
+type ProductSQLite struct {
+	Id          sql.NullInt32
+	Description sql.NullString
+	Price       sql.NullFloat64
+}
+
+// StorageProductSQLite is a struct that represents a product storage in SQLite for StorageProduct interface
+type StorageProductSQLite struct {
+	// db is the database or the transaction the operations run on
+	db sqltx.Executor
+}
+
+// InTx returns a copy of the storage whose operations run in the transaction tx (see sqltx.Runner)
+func (s *StorageProductSQLite) InTx(tx *sql.Tx) *StorageProductSQLite {
+	return &StorageProductSQLite{db: tx}
+}
+
+// ReadAll returns all products
+func (s *StorageProductSQLite) ReadAll(ctx context.Context) (ps []*Product, err error) {
+	ps, err = s.read(ctx, "SELECT id, `description`, price FROM products")
+	return
+}
+
+// ReadPage returns the page of products of the query and the number of products that match its filters
+func (s *StorageProductSQLite) ReadPage(ctx context.Context, q query.Query) (ps []*Product, total int, err error) {
+	where, whereArgs := q.Where(productColumns)
+	page, pageArgs := q.Page(productColumns)
+
+	// count
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products"+where, whereArgs...).Scan(&total)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	// page
+	ps, err = s.read(ctx, "SELECT id, `description`, price FROM products"+where+page, append(whereArgs, pageArgs...)...)
+	return
+}
+
+// read returns the products selected by the query with the args
+func (s *StorageProductSQLite) read(ctx context.Context, query string, args ...any) (ps []*Product, err error) {
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var rows *sql.Rows
+	rows, err = stmt.QueryContext(ctx, args...)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer rows.Close()
+
+	// iterate rows
+	for rows.Next() {
+		// scan row
+		var psSQLite ProductSQLite
+		err = rows.Scan(&psSQLite.Id, &psSQLite.Description, &psSQLite.Price)
+		if err != nil {
+			err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+			return
+		}
+
+		// serialization
+		p := new(Product)
+		if psSQLite.Id.Valid {
+			p.Id = int(psSQLite.Id.Int32)
+		}
+		if psSQLite.Description.Valid {
+			p.Description = psSQLite.Description.String
+		}
+		if psSQLite.Price.Valid {
+			p.Price = psSQLite.Price.Float64
+		}
+
+		// append to list
+		ps = append(ps, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	return
+}
+
+// ReadById returns the product with the given id
+func (s *StorageProductSQLite) ReadById(ctx context.Context, id int) (p *Product, err error) {
+	// query
+	query := "SELECT id, `description`, price FROM products WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var psSQLite ProductSQLite
+	err = stmt.QueryRowContext(ctx, id).Scan(&psSQLite.Id, &psSQLite.Description, &psSQLite.Price)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w. %v", ErrStorageProductNotFound, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	// serialization
+	p = new(Product)
+	if psSQLite.Id.Valid {
+		p.Id = int(psSQLite.Id.Int32)
+	}
+	if psSQLite.Description.Valid {
+		p.Description = psSQLite.Description.String
+	}
+	if psSQLite.Price.Valid {
+		p.Price = psSQLite.Price.Float64
+	}
+
+	return
+}
+
+// Create inserts a new product
+func (s *StorageProductSQLite) Create(ctx context.Context, p *Product) (err error) {
+	// deserialization
+	var psSQLite ProductSQLite
+	if p.Id != 0 {
+		psSQLite.Id.Valid = true
+		psSQLite.Id.Int32 = int32(p.Id)
+	}
+	if p.Description != "" {
+		psSQLite.Description.Valid = true
+		psSQLite.Description.String = p.Description
+	}
+	if p.Price != 0 {
+		psSQLite.Price.Valid = true
+		psSQLite.Price.Float64 = p.Price
+	}
+
+	// query
+	query := "INSERT INTO products (id, `description`, price) VALUES (?, ?, ?)"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var res sql.Result
+	res, err = stmt.ExecContext(ctx, psSQLite.Id, psSQLite.Description, psSQLite.Price)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = res.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	if rowsAffected != 1 {
+		err = fmt.Errorf("%w. %v", ErrStorageProductInternal, "rows affected != 1")
+		return
+	}
+
+	// get last insert id
+	var id int64
+	id, err = res.LastInsertId()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	// update product id
+	p.Id = int(id)
+
+	return
+}
+
+// Update replaces the product with the same id
+func (s *StorageProductSQLite) Update(ctx context.Context, p *Product) (err error) {
+	// deserialization
+	var psSQLite ProductSQLite
+	if p.Description != "" {
+		psSQLite.Description.Valid = true
+		psSQLite.Description.String = p.Description
+	}
+	if p.Price != 0 {
+		psSQLite.Price.Valid = true
+		psSQLite.Price.Float64 = p.Price
+	}
+
+	// query
+	query := "UPDATE products SET `description` = ?, price = ? WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, psSQLite.Description, psSQLite.Price, p.Id)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	// check rows affected (0 when the product does not exist or nothing changed)
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		_, err = s.ReadById(ctx, p.Id)
+		return
+	}
+
+	return
+}
+
+// Delete removes the product with the given id
+func (s *StorageProductSQLite) Delete(ctx context.Context, id int) (err error) {
+	// query
+	query := "DELETE FROM products WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		if sqlitedb.IsForeignKeyError(err) {
+			err = fmt.Errorf("%w. %v", ErrStorageProductReferenced, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		err = ErrStorageProductNotFound
+		return
+	}
+
+	return
+}
+
+// ReadTopSold returns the limit products with the highest total quantity sold
+func (s *StorageProductSQLite) ReadTopSold(ctx context.Context, limit int) (ps []*ProductTopSold, err error) {
+	// query
+	query := "SELECT p.id, p.`description`, COALESCE(SUM(sa.quantity), 0) AS total " +
+		"FROM products p INNER JOIN sales sa ON sa.product_id = p.id " +
+		"GROUP BY p.id, p.`description` ORDER BY total DESC, p.id LIMIT ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var rows *sql.Rows
+	rows, err = stmt.QueryContext(ctx, limit)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+	defer rows.Close()
+
+	// iterate rows
+	for rows.Next() {
+		// scan row
+		var description sql.NullString
+		p := new(ProductTopSold)
+		err = rows.Scan(&p.Id, &description, &p.Total)
+		if err != nil {
+			err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+			return
+		}
+		if description.Valid {
+			p.Description = description.String
+		}
+
+		// append to list
+		ps = append(ps, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageProductInternal, err)
+		return
+	}
+
+	return
+}
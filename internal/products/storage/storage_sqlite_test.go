@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"app/internal/sqlitedb"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StorageProductSQLite
+func TestStorageProductSQLite_ReadTopSold(t *testing.T) {
+	t.Run("ranked by quantity sold", func(t *testing.T) {
+		// arrange
+		db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+		require.NoError(t, err)
+		defer db.Close()
+		st := NewStorageProductSQLite(db)
+		require.NoError(t, st.Create(context.Background(), &Product{Description: "Beans", Price: 12.89}))
+		require.NoError(t, st.Create(context.Background(), &Product{Description: "Juice", Price: 46.05}))
+		_, err = db.Exec("INSERT INTO invoices (id) VALUES (1); INSERT INTO sales (quantity, product_id, invoice_id) VALUES (3, 1, 1), (5, 2, 1), (4, 1, 1)")
+		require.NoError(t, err)
+
+		// act
+		ps, err := st.ReadTopSold(context.Background(), 1)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []*ProductTopSold{{Id: 1, Description: "Beans", Total: 7}}, ps)
+	})
+}
+
+func TestStorageProductSQLite_Context(t *testing.T) {
+	t.Run("cancelled context", func(t *testing.T) {
+		// arrange
+		db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+		require.NoError(t, err)
+		defer db.Close()
+		st := NewStorageProductSQLite(db)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// act
+		_, err = st.ReadAll(ctx)
+
+		// assert
+		require.ErrorIs(t, err, ErrStorageProductInternal)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,530 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is the type of the values of a field
+type Kind int
+
+const (
+	KindInt Kind = iota
+	KindFloat
+	KindString
+	KindBool
+	KindTime
+)
+
+// Fields are the fields a read can be filtered and sorted by, with the kind of their values.
+// Every set of fields must have an "id" field, used as the last sort so pages are stable
+type Fields map[string]Kind
+
+// Op is the comparison of a filter
+type Op int
+
+const (
+	// OpEq matches values equal to the filter value
+	OpEq Op = iota
+	// OpGte matches values greater than or equal to the filter value
+	OpGte
+	// OpLte matches values less than or equal to the filter value
+	OpLte
+	// OpLt matches values less than the filter value
+	OpLt
+)
+
+// Filter is a struct that represents a condition on the value of a field
+type Filter struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+// Sort is a struct that represents the order by a field
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Query is a struct that represents the filters, order and page of a read
+type Query struct {
+	Filters []Filter
+	Sort    []Sort
+	// Limit is the maximum number of rows (0 is no limit)
+	Limit  int
+	Offset int
+	// After are the values of the sort fields (followed by id) of the last row of the previous page,
+	// the page starts at the row after it (empty to page by Offset)
+	After []any
+}
+
+const (
+	// DefaultLimit is the limit of a parsed query without limit
+	DefaultLimit = 100
+	// MaxLimit is the maximum limit of a parsed query
+	MaxLimit = 1000
+)
+
+// Names of the url parameters that are not filters
+const (
+	ParamLimit  = "limit"
+	ParamOffset = "offset"
+	ParamAfter  = "after"
+	ParamSort   = "sort"
+)
+
+// DateLayout is the layout of a date without time
+const DateLayout = "2006-01-02"
+
+// TimeLayouts are the layouts accepted for the values of KindTime fields
+var TimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", DateLayout}
+
+var (
+	// ErrQueryInvalid is returned when the url parameters of a query are invalid
+	ErrQueryInvalid = errors.New("query invalid")
+)
+
+// Parse returns the query of the url values:
+//   - limit: number of rows, from 1 to MaxLimit (DefaultLimit if missing)
+//   - offset: number of rows skipped, or after: the cursor returned by Next for the previous page
+//     (keyset paging: the rows inserted or deleted before the cursor do not shift the next pages)
+//   - sort: comma separated fields, descending when prefixed with -
+//   - field=value: rows whose field equals value
+func Parse(v url.Values, fields Fields) (q Query, err error) {
+	q.Limit = DefaultLimit
+
+	// page
+	if s := v.Get(ParamLimit); s != "" {
+		q.Limit, err = strconv.Atoi(s)
+		if err != nil || q.Limit < 1 || q.Limit > MaxLimit {
+			err = fmt.Errorf("%w. %s must be an integer between 1 and %d", ErrQueryInvalid, ParamLimit, MaxLimit)
+			return
+		}
+	}
+	if v.Has(ParamOffset) && v.Has(ParamAfter) {
+		err = fmt.Errorf("%w. %s and %s can not be used together", ErrQueryInvalid, ParamOffset, ParamAfter)
+		return
+	}
+	if s := v.Get(ParamOffset); s != "" {
+		q.Offset, err = strconv.Atoi(s)
+		if err != nil || q.Offset < 0 {
+			err = fmt.Errorf("%w. %s must be a non-negative integer", ErrQueryInvalid, ParamOffset)
+			return
+		}
+	}
+
+	// sort
+	if s := v.Get(ParamSort); s != "" {
+		for _, name := range strings.Split(s, ",") {
+			sr := Sort{Field: strings.TrimSpace(name)}
+			if strings.HasPrefix(sr.Field, "-") {
+				sr.Field, sr.Desc = sr.Field[1:], true
+			}
+			if _, ok := fields[sr.Field]; !ok {
+				err = fmt.Errorf("%w. %s: unknown field %q (fields: %s)", ErrQueryInvalid, ParamSort, sr.Field, fields.names())
+				return
+			}
+			q.Sort = append(q.Sort, sr)
+		}
+	}
+
+	// cursor (of the same sort, whose fields set the kinds of its values)
+	if s := v.Get(ParamAfter); s != "" {
+		q.After, err = decodeCursor(s, q.sort(), fields)
+		if err != nil {
+			err = fmt.Errorf("%w. %s must be a cursor returned by a previous page with the same sort", ErrQueryInvalid, ParamAfter)
+			return
+		}
+	}
+
+	// filters (in a fixed order, so the same url always builds the same query)
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch key {
+		case ParamLimit, ParamOffset, ParamAfter, ParamSort:
+			continue
+		}
+		kind, ok := fields[key]
+		if !ok {
+			err = fmt.Errorf("%w. unknown parameter %q (fields: %s)", ErrQueryInvalid, key, fields.names())
+			return
+		}
+		var value any
+		value, err = parseValue(kind, v.Get(key))
+		if err != nil {
+			err = fmt.Errorf("%w. %s: %v", ErrQueryInvalid, key, err)
+			return
+		}
+		q.Filters = append(q.Filters, Filter{Field: key, Value: value})
+	}
+
+	return
+}
+
+// Next returns the cursor of the page after page (the items read for the query), or an empty string if it is
+// the last one. value returns the value of a field of an item, as in Apply.
+// A page read with a cursor does not know how many rows are left, so a full one always has a next cursor
+func Next[T any](q Query, total int, page []T, value func(item T, field string) any) (cursor string) {
+	if q.Limit == 0 || len(page) < q.Limit || (len(q.After) == 0 && q.Offset+len(page) >= total) {
+		return
+	}
+
+	last := page[len(page)-1]
+	sorts := q.sort()
+	values := make([]any, 0, len(sorts))
+	for _, s := range sorts {
+		values = append(values, value(last, s.Field))
+	}
+	cursor = encodeCursor(sorts, values)
+	return
+}
+
+// Where returns the WHERE clause of the filters (empty without filters) and its args (times in UTC),
+// with the columns (or expressions) of each field
+func (q Query) Where(columns map[string]string) (clause string, args []any) {
+	if len(q.Filters) == 0 {
+		return
+	}
+
+	conds := make([]string, 0, len(q.Filters))
+	for _, f := range q.Filters {
+		conds = append(conds, columns[f.Field]+" "+f.Op.sql()+" ?")
+		args = append(args, sqlArg(f.Value))
+	}
+	clause = " WHERE " + strings.Join(conds, " AND ")
+	return
+}
+
+// Page returns the clauses of the page of the query and their args, with the columns (or expressions) of each field:
+// the condition of After (to follow the Where clause), ORDER BY and LIMIT. The rows are ordered by id last so
+// pages are stable, and the rows after a cursor are the ones whose (sort fields, id) come after its values
+func (q Query) Page(columns map[string]string) (clause string, args []any) {
+	sorts := q.sort()
+
+	// keyset: (s1 > v1) OR (s1 = v1 AND s2 > v2) OR ... (< for descending fields)
+	if len(q.After) > 0 {
+		ors := make([]string, 0, len(sorts))
+		for i, s := range sorts {
+			conds := make([]string, 0, i+1)
+			for j := 0; j < i; j++ {
+				conds = append(conds, columns[sorts[j].Field]+" = ?")
+				args = append(args, sqlArg(q.After[j]))
+			}
+			op := ">"
+			if s.Desc {
+				op = "<"
+			}
+			conds = append(conds, columns[s.Field]+" "+op+" ?")
+			args = append(args, sqlArg(q.After[i]))
+			ors = append(ors, "("+strings.Join(conds, " AND ")+")")
+		}
+		clause = " WHERE "
+		if len(q.Filters) > 0 {
+			clause = " AND "
+		}
+		clause += "(" + strings.Join(ors, " OR ") + ")"
+	}
+
+	// order
+	orders := make([]string, 0, len(sorts))
+	for _, s := range sorts {
+		order := columns[s.Field]
+		if s.Desc {
+			order += " DESC"
+		}
+		orders = append(orders, order)
+	}
+	clause += " ORDER BY " + strings.Join(orders, ", ")
+
+	// page
+	if q.Limit == 0 && q.Offset == 0 {
+		return
+	}
+	limit := q.Limit
+	if limit == 0 {
+		limit = math.MaxInt64
+	}
+	clause += " LIMIT ? OFFSET ?"
+	args = append(args, limit, q.Offset)
+	return
+}
+
+// Apply returns the page of items for the query and the number of items that match its filters.
+// value returns the value of a field of an item, with the type of its kind (int, float64, string, bool or time.Time)
+func Apply[T any](items []T, q Query, value func(item T, field string) any) (page []T, total int) {
+	// filter
+	for _, item := range items {
+		match := true
+		for _, f := range q.Filters {
+			if !f.Op.match(compare(value(item, f.Field), f.Value)) {
+				match = false
+				break
+			}
+		}
+		if match {
+			page = append(page, item)
+		}
+	}
+	total = len(page)
+
+	// sort
+	sorts := q.sort()
+	sort.SliceStable(page, func(i, j int) bool {
+		for _, s := range sorts {
+			c := compare(value(page[i], s.Field), value(page[j], s.Field))
+			if c == 0 {
+				continue
+			}
+			if s.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+
+	// keyset (the items are sorted, so the page starts at the first one after the cursor)
+	if len(q.After) > 0 {
+		start := sort.Search(len(page), func(i int) bool {
+			for k, s := range sorts {
+				c := compare(value(page[i], s.Field), q.After[k])
+				if c == 0 {
+					continue
+				}
+				return c > 0 != s.Desc
+			}
+			return false
+		})
+		page = page[start:]
+	}
+
+	// page
+	if q.Offset >= len(page) {
+		page = nil
+		return
+	}
+	page = page[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(page) {
+		page = page[:q.Limit]
+	}
+	return
+}
+
+// sort returns the sort of the query followed by id (unless the query already sorts by id)
+func (q Query) sort() (s []Sort) {
+	s = append(s, q.Sort...)
+	for _, sr := range q.Sort {
+		if sr.Field == "id" {
+			return
+		}
+	}
+	s = append(s, Sort{Field: "id"})
+	return
+}
+
+// names returns the sorted names of the fields, comma separated
+func (f Fields) names() string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// parseValue returns the value of a url parameter for a field of the kind
+func parseValue(kind Kind, s string) (value any, err error) {
+	switch kind {
+	case KindInt:
+		value, err = strconv.Atoi(s)
+		if err != nil {
+			err = fmt.Errorf("%q is not an integer", s)
+		}
+	case KindFloat:
+		value, err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			err = fmt.Errorf("%q is not a number", s)
+		}
+	case KindBool:
+		value, err = strconv.ParseBool(s)
+		if err != nil {
+			err = fmt.Errorf("%q is not a boolean", s)
+		}
+	case KindTime:
+		value, _, err = ParseTime(s)
+	default:
+		value = s
+	}
+	return
+}
+
+// ParseTime returns the time of s in any of the TimeLayouts (in UTC when the layout has no zone) and its layout
+func ParseTime(s string) (t time.Time, layout string, err error) {
+	for _, layout = range TimeLayouts {
+		t, err = time.Parse(layout, s)
+		if err == nil {
+			return
+		}
+	}
+
+	layout = ""
+	err = fmt.Errorf("%q is not a date (formats: %s)", s, strings.Join(TimeLayouts, ", "))
+	return
+}
+
+// sqlArg returns the arg of a value of a field: times in UTC, so they compare in time order with the
+// datetimes stored in UTC (SQLite compares them as text, keeping the offset they are bound with)
+func sqlArg(value any) any {
+	if t, ok := value.(time.Time); ok {
+		return t.UTC()
+	}
+	return value
+}
+
+// sql returns the SQL operator of the comparison
+func (o Op) sql() string {
+	switch o {
+	case OpGte:
+		return ">="
+	case OpLte:
+		return "<="
+	case OpLt:
+		return "<"
+	}
+	return "="
+}
+
+// match reports whether the result of compare(value, filter value) matches the comparison
+func (o Op) match(c int) bool {
+	switch o {
+	case OpGte:
+		return c >= 0
+	case OpLte:
+		return c <= 0
+	case OpLt:
+		return c < 0
+	}
+	return c == 0
+}
+
+// compare returns -1, 0 or +1 when a is less than, equal to or greater than b (values of the same kind)
+func compare(a, b any) int {
+	switch a := a.(type) {
+	case int:
+		return cmpOrdered(a, b.(int))
+	case float64:
+		return cmpOrdered(a, b.(float64))
+	case string:
+		return cmpOrdered(a, b.(string))
+	case bool:
+		switch b := b.(bool); {
+		case a == b:
+			return 0
+		case !a:
+			return -1
+		}
+		return 1
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+	return 0
+}
+
+// cmpOrdered compares two ordered values
+func cmpOrdered[T int | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// cursor is the content of a cursor: the sort it was built for and the values of its fields
+type cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+// encodeCursor returns the opaque cursor of the values of the sorts
+func encodeCursor(sorts []Sort, values []any) string {
+	c := cursor{Sort: sortKey(sorts), Values: make([]string, 0, len(values))}
+	for _, v := range values {
+		c.Values = append(c.Values, formatValue(v))
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor returns the values of a cursor returned by encodeCursor for the sorts, with the kinds of the fields
+func decodeCursor(s string, sorts []Sort, fields Fields) (values []any, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return
+	}
+	var c cursor
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return
+	}
+	if c.Sort != sortKey(sorts) || len(c.Values) != len(sorts) {
+		err = errors.New("cursor of another sort")
+		return
+	}
+
+	values = make([]any, 0, len(sorts))
+	for i, sr := range sorts {
+		var v any
+		v, err = parseValue(fields[sr.Field], c.Values[i])
+		if err != nil {
+			values = nil
+			return
+		}
+		values = append(values, v)
+	}
+	return
+}
+
+// sortKey returns the sorts as in the sort parameter
+func sortKey(sorts []Sort) string {
+	names := make([]string, 0, len(sorts))
+	for _, s := range sorts {
+		name := s.Field
+		if s.Desc {
+			name = "-" + name
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ",")
+}
+
+// formatValue returns a value of a field as parseValue reads it
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,249 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testFields = Fields{"id": KindInt, "name": KindString, "price": KindFloat, "active": KindBool, "date": KindTime}
+
+// Tests for Parse function
+func TestParse(t *testing.T) {
+	type input struct{ query string }
+	type output struct {
+		q   Query
+		err error
+	}
+	type testCase struct {
+		name   string
+		input  input
+		output output
+	}
+
+	cases := []testCase{
+		{
+			name:   "defaults",
+			input:  input{query: ""},
+			output: output{q: Query{Limit: DefaultLimit}},
+		},
+		{
+			name:  "page, sort and filters",
+			input: input{query: "limit=10&offset=20&sort=-price,name&active=true&date=2022-05-15"},
+			output: output{q: Query{
+				Filters: []Filter{{Field: "active", Value: true}, {Field: "date", Value: time.Date(2022, 5, 15, 0, 0, 0, 0, time.UTC)}},
+				Sort:    []Sort{{Field: "price", Desc: true}, {Field: "name"}},
+				Limit:   10,
+				Offset:  20,
+			}},
+		},
+		{
+			name:   "cursor",
+			input:  input{query: "limit=5&sort=-date&after=" + encodeCursor([]Sort{{Field: "date", Desc: true}, {Field: "id"}}, []any{time.Date(2022, 5, 15, 10, 30, 0, 0, time.UTC), 7})},
+			output: output{q: Query{Sort: []Sort{{Field: "date", Desc: true}}, Limit: 5, After: []any{time.Date(2022, 5, 15, 10, 30, 0, 0, time.UTC), 7}}},
+		},
+		{
+			name:   "limit out of range",
+			input:  input{query: "limit=1001"},
+			output: output{err: ErrQueryInvalid},
+		},
+		{
+			name:   "negative offset",
+			input:  input{query: "offset=-1"},
+			output: output{err: ErrQueryInvalid},
+		},
+		{
+			name:   "offset and after",
+			input:  input{query: "offset=1&after=" + encodeCursor([]Sort{{Field: "id"}}, []any{1})},
+			output: output{err: ErrQueryInvalid},
+		},
+		{
+			name:   "cursor of another sort",
+			input:  input{query: "sort=name&after=" + encodeCursor([]Sort{{Field: "id"}}, []any{1})},
+			output: output{err: ErrQueryInvalid},
+		},
+		{
+			name:   "invalid cursor",
+			input:  input{query: "after=abc"},
+			output: output{err: ErrQueryInvalid},
+		},
+		{
+			name:   "sort by unknown field",
+			input:  input{query: "sort=password"},
+			output: output{err: ErrQueryInvalid},
+		},
+		{
+			name:   "unknown parameter",
+			input:  input{query: "password=1"},
+			output: output{err: ErrQueryInvalid},
+		},
+		{
+			name:   "invalid filter value",
+			input:  input{query: "id=one"},
+			output: output{err: ErrQueryInvalid},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			v, err := url.ParseQuery(c.input.query)
+			require.NoError(t, err)
+
+			// act
+			q, err := Parse(v, testFields)
+
+			// assert
+			if c.output.err != nil {
+				require.ErrorIs(t, err, c.output.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.output.q, q)
+		})
+	}
+}
+
+// Tests for Next function
+func TestNext(t *testing.T) {
+	// arrange
+	type item struct {
+		id    int
+		price float64
+	}
+	value := func(it *item, field string) any {
+		if field == "price" {
+			return it.price
+		}
+		return it.id
+	}
+	page := []*item{{id: 4, price: 3.5}, {id: 2, price: 1.25}}
+	q := Query{Sort: []Sort{{Field: "price", Desc: true}}, Limit: 2}
+
+	t.Run("next page", func(t *testing.T) {
+		// act
+		cursor := Next(q, 5, page, value)
+		after, err := decodeCursor(cursor, q.sort(), testFields)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []any{1.25, 2}, after)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		// act
+		cursor := Next(q, 2, page, value)
+
+		// assert
+		require.Empty(t, cursor)
+	})
+
+	t.Run("short page after a cursor", func(t *testing.T) {
+		// arrange
+		q := q
+		q.After = []any{3.5, 4}
+
+		// act
+		cursor := Next(q, 5, page[1:], value)
+
+		// assert
+		require.Empty(t, cursor)
+	})
+}
+
+// Tests for Query.Where and Query.Page methods
+func TestQuery_SQL(t *testing.T) {
+	// arrange
+	columns := map[string]string{"id": "id", "name": "COALESCE(name, '')", "price": "price"}
+	q := Query{
+		Filters: []Filter{{Field: "name", Value: "beans"}},
+		Sort:    []Sort{{Field: "price", Desc: true}},
+		Limit:   10,
+		Offset:  5,
+	}
+
+	// act
+	where, whereArgs := q.Where(columns)
+	page, pageArgs := q.Page(columns)
+
+	// assert
+	require.Equal(t, " WHERE COALESCE(name, '') = ?", where)
+	require.Equal(t, []any{"beans"}, whereArgs)
+	require.Equal(t, " ORDER BY price DESC, id LIMIT ? OFFSET ?", page)
+	require.Equal(t, []any{10, 5}, pageArgs)
+
+	t.Run("after a cursor", func(t *testing.T) {
+		// arrange
+		q := Query{Sort: []Sort{{Field: "price", Desc: true}}, Limit: 10, After: []any{2.5, 7}}
+
+		// act
+		page, pageArgs := q.Page(columns)
+
+		// assert
+		require.Equal(t, " WHERE ((price < ?) OR (price = ? AND id > ?)) ORDER BY price DESC, id LIMIT ? OFFSET ?", page)
+		require.Equal(t, []any{2.5, 2.5, 7, 10, 0}, pageArgs)
+	})
+
+	t.Run("times in UTC", func(t *testing.T) {
+		// arrange
+		from := time.Date(2022, 3, 31, 22, 30, 0, 0, time.FixedZone("", -5*3600))
+		q := Query{Filters: []Filter{{Field: "datetime", Op: OpGte, Value: from}}}
+
+		// act
+		_, whereArgs := q.Where(map[string]string{"datetime": "datetime"})
+
+		// assert
+		require.Equal(t, []any{time.Date(2022, 4, 1, 3, 30, 0, 0, time.UTC)}, whereArgs)
+	})
+}
+
+// Tests for Apply function
+func TestApply(t *testing.T) {
+	// arrange
+	type item struct {
+		id    int
+		name  string
+		price float64
+	}
+	items := []*item{{1, "a", 3}, {2, "b", 1}, {3, "a", 2}, {4, "a", 3}}
+	value := func(it *item, field string) any {
+		switch field {
+		case "id":
+			return it.id
+		case "name":
+			return it.name
+		case "price":
+			return it.price
+		}
+		return nil
+	}
+	q := Query{
+		Filters: []Filter{{Field: "name", Value: "a"}},
+		Sort:    []Sort{{Field: "price", Desc: true}},
+		Limit:   2,
+		Offset:  1,
+	}
+
+	// act
+	page, total := Apply(items, q, value)
+
+	// assert
+	require.Equal(t, 3, total)
+	require.Equal(t, []*item{items[3], items[2]}, page)
+
+	t.Run("after a cursor", func(t *testing.T) {
+		// arrange
+		q := q
+		q.Offset = 0
+		q.After = []any{3.0, 1}
+
+		// act
+		page, total := Apply(items, q, value)
+
+		// assert
+		require.Equal(t, 3, total)
+		require.Equal(t, []*item{items[3], items[2]}, page)
+	})
+}
@@ -1,6 +1,11 @@
 package storage
 
-import "errors"
+import (
+	"app/internal/query"
+	"context"
+	"errors"
+	"fmt"
+)
 
 // Sale is a struct that represents a sale
 type Sale struct {
@@ -10,13 +15,48 @@ type Sale struct {
 	InvoiceId  int
 }
 
+// SaleFields are the fields of a sale that ReadPage can filter and sort by
+var SaleFields = query.Fields{
+	"id":         query.KindInt,
+	"quantity":   query.KindInt,
+	"product_id": query.KindInt,
+	"invoice_id": query.KindInt,
+}
+
+// SaleValue returns the value of a field of SaleFields of the sale (the value of its column in the sql storages)
+func SaleValue(sa *Sale, field string) any {
+	switch field {
+	case "id":
+		return sa.Id
+	case "quantity":
+		return sa.Quantity
+	case "product_id":
+		return sa.ProductId
+	case "invoice_id":
+		return sa.InvoiceId
+	}
+	return nil
+}
+
 // StorageSale is an interface that represents a sale storage
 type StorageSale interface {
 	// ReadAll returns all sales
-	ReadAll() (ss []*Sale, err error)
+	ReadAll(ctx context.Context) (ss []*Sale, err error)
+
+	// ReadPage returns the page of sales of the query and the number of sales that match its filters
+	ReadPage(ctx context.Context, q query.Query) (ss []*Sale, total int, err error)
+
+	// ReadById returns the sale with the given id
+	ReadById(ctx context.Context, id int) (s *Sale, err error)
+
+	// Create inserts a new sale (the id is kept if set, otherwise it is generated)
+	Create(ctx context.Context, s *Sale) (err error)
+
+	// Update replaces the sale with the same id
+	Update(ctx context.Context, s *Sale) (err error)
 
-	// Create inserts a new sale
-	Create(s *Sale) (err error)
+	// Delete removes the sale with the given id
+	Delete(ctx context.Context, id int) (err error)
 }
 
 var (
@@ -26,4 +66,8 @@ var (
 	ErrStorageSaleNotFound = errors.New("sale not found")
 	// ErrStorageSaleRelation is returned when a sale relation is not found
 	ErrStorageSaleRelation = errors.New("sale relation not found")
+	// ErrStorageSaleRelationProduct is returned when the product of a sale is not found (wraps ErrStorageSaleRelation)
+	ErrStorageSaleRelationProduct = fmt.Errorf("%w: product", ErrStorageSaleRelation)
+	// ErrStorageSaleRelationInvoice is returned when the invoice of a sale is not found (wraps ErrStorageSaleRelation)
+	ErrStorageSaleRelationInvoice = fmt.Errorf("%w: invoice", ErrStorageSaleRelation)
 )
\ No newline at end of file
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"app/internal/jsondb"
+	"app/internal/memdb"
+	"app/internal/query"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// NewStorageSaleJSON returns a new instance of StorageSaleJSON for the json file at path.
+// The related tables are read from the files with their canonical names in the same directory (see jsondb.FilesFor)
+func NewStorageSaleJSON(path string) *StorageSaleJSON {
+	return &StorageSaleJSON{db: jsondb.New(jsondb.FilesFor(jsondb.TableSales, path))}
+}
+
+// StorageSaleJSON is a struct that represents a sale storage in a json file for StorageSale interface.
+// Each operation works on the data loaded from the files, with the same semantics as StorageSaleMap
+type StorageSaleJSON struct {
+	db *jsondb.DB
+}
+
+// ReadAll returns all sales
+func (s *StorageSaleJSON) ReadAll(ctx context.Context) (ss []*Sale, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		ss, err = NewStorageSaleMap(mdb).ReadAll(ctx)
+		return
+	})
+	err = saleJSONError(err)
+	return
+}
+
+// ReadPage returns the page of sales of the query and the number of sales that match its filters
+func (s *StorageSaleJSON) ReadPage(ctx context.Context, q query.Query) (ss []*Sale, total int, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		ss, total, err = NewStorageSaleMap(mdb).ReadPage(ctx, q)
+		return
+	})
+	err = saleJSONError(err)
+	return
+}
+
+// ReadById returns the sale with the given id
+func (s *StorageSaleJSON) ReadById(ctx context.Context, id int) (sa *Sale, err error) {
+	err = s.db.View(func(mdb *memdb.DB) (err error) {
+		sa, err = NewStorageSaleMap(mdb).ReadById(ctx, id)
+		return
+	})
+	err = saleJSONError(err)
+	return
+}
+
+// Create inserts a new sale (the id is kept if set, otherwise it is generated)
+func (s *StorageSaleJSON) Create(ctx context.Context, sa *Sale) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageSaleMap(mdb).Create(ctx, sa)
+		return
+	}, jsondb.TableSales)
+	err = saleJSONError(err)
+	return
+}
+
+// Update replaces the sale with the same id
+func (s *StorageSaleJSON) Update(ctx context.Context, sa *Sale) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageSaleMap(mdb).Update(ctx, sa)
+		return
+	}, jsondb.TableSales)
+	err = saleJSONError(err)
+	return
+}
+
+// Delete removes the sale with the given id
+func (s *StorageSaleJSON) Delete(ctx context.Context, id int) (err error) {
+	err = s.db.Update(func(mdb *memdb.DB) (err error) {
+		err = NewStorageSaleMap(mdb).Delete(ctx, id)
+		return
+	}, jsondb.TableSales)
+	err = saleJSONError(err)
+	return
+}
+
+// saleJSONError wraps the errors of the json files as internal storage errors
+func saleJSONError(err error) error {
+	if errors.Is(err, jsondb.ErrJSONDBFile) {
+		return fmt.Errorf("%w. %v", ErrStorageSaleInternal, err)
+	}
+	return err
+}
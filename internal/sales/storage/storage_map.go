@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"app/internal/memdb"
+	"app/internal/query"
+	"context"
+	"fmt"
+	"sort"
+)
+
+// NewStorageSaleMap returns a new instance of StorageSaleMap
+func NewStorageSaleMap(db *memdb.DB) *StorageSaleMap {
+	return &StorageSaleMap{db: db}
+}
+
+// StorageSaleMap is a struct that represents a sale storage in memory for StorageSale interface
+type StorageSaleMap struct {
+	db *memdb.DB
+}
+
+// ReadAll returns all sales
+func (s *StorageSaleMap) ReadAll(ctx context.Context) (ss []*Sale, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		for _, row := range t.Sales {
+			ss = append(ss, saleFromRow(row))
+		}
+		return
+	})
+
+	// sort by id (primary key order)
+	sort.Slice(ss, func(i, j int) bool { return ss[i].Id < ss[j].Id })
+	return
+}
+
+// ReadPage returns the page of sales of the query and the number of sales that match its filters
+func (s *StorageSaleMap) ReadPage(ctx context.Context, q query.Query) (ss []*Sale, total int, err error) {
+	ss, err = s.ReadAll(ctx)
+	if err != nil {
+		return
+	}
+
+	ss, total = query.Apply(ss, q, SaleValue)
+	return
+}
+
+// ReadById returns the sale with the given id
+func (s *StorageSaleMap) ReadById(ctx context.Context, id int) (sa *Sale, err error) {
+	err = s.db.View(func(t *memdb.Tables) (err error) {
+		row, ok := t.Sales[id]
+		if !ok {
+			err = ErrStorageSaleNotFound
+			return
+		}
+
+		sa = saleFromRow(row)
+		return
+	})
+	return
+}
+
+// Create inserts a new sale (the id is kept if set, otherwise it is generated)
+func (s *StorageSaleMap) Create(ctx context.Context, sa *Sale) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		// check duplicated id
+		if _, ok := t.Sales[sa.Id]; ok {
+			err = fmt.Errorf("%w. duplicated id %d", ErrStorageSaleInternal, sa.Id)
+			return
+		}
+
+		// check relations
+		err = saleRelations(t, sa)
+		if err != nil {
+			return
+		}
+
+		// insert
+		sa.Id = t.SaleId(sa.Id)
+		t.Sales[sa.Id] = saleToRow(sa)
+		return
+	})
+	return
+}
+
+// Update replaces the sale with the same id
+func (s *StorageSaleMap) Update(ctx context.Context, sa *Sale) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		if _, ok := t.Sales[sa.Id]; !ok {
+			err = ErrStorageSaleNotFound
+			return
+		}
+
+		// check relations
+		err = saleRelations(t, sa)
+		if err != nil {
+			return
+		}
+
+		t.Sales[sa.Id] = saleToRow(sa)
+		return
+	})
+	return
+}
+
+// Delete removes the sale with the given id
+func (s *StorageSaleMap) Delete(ctx context.Context, id int) (err error) {
+	err = s.db.Update(func(t *memdb.Tables) (err error) {
+		if _, ok := t.Sales[id]; !ok {
+			err = ErrStorageSaleNotFound
+			return
+		}
+
+		delete(t.Sales, id)
+		return
+	})
+	return
+}
+
+// saleRelations checks that the product and the invoice of the sale exist (a zero id is a null relation)
+func saleRelations(t *memdb.Tables, sa *Sale) (err error) {
+	if sa.ProductId != 0 {
+		if _, ok := t.Products[sa.ProductId]; !ok {
+			err = fmt.Errorf("%w. product %d", ErrStorageSaleRelationProduct, sa.ProductId)
+			return
+		}
+	}
+	if sa.InvoiceId != 0 {
+		if _, ok := t.Invoices[sa.InvoiceId]; !ok {
+			err = fmt.Errorf("%w. invoice %d", ErrStorageSaleRelationInvoice, sa.InvoiceId)
+			return
+		}
+	}
+	return
+}
+
+// saleFromRow returns a sale with the values of the row
+func saleFromRow(row *memdb.SaleRow) *Sale {
+	return &Sale{
+		Id:        row.Id,
+		Quantity:  row.Quantity,
+		ProductId: row.ProductId,
+		InvoiceId: row.InvoiceId,
+	}
+}
+
+// saleToRow returns a row with the values of the sale
+func saleToRow(sa *Sale) *memdb.SaleRow {
+	return &memdb.SaleRow{
+		Id:        sa.Id,
+		Quantity:  sa.Quantity,
+		ProductId: sa.ProductId,
+		InvoiceId: sa.InvoiceId,
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"app/internal/memdb"
+	"app/internal/query"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StorageSaleMap
+func TestStorageSaleMap_Create(t *testing.T) {
+	// arrange
+	db := memdb.NewDB()
+	_ = db.Update(func(t *memdb.Tables) (err error) {
+		t.Products[1] = &memdb.ProductRow{Id: 1}
+		t.Invoices[1] = &memdb.InvoiceRow{Id: 1}
+		return
+	})
+	st := NewStorageSaleMap(db)
+
+	t.Run("valid relations", func(t *testing.T) {
+		// act
+		sa := &Sale{Quantity: 2, ProductId: 1, InvoiceId: 1}
+		err := st.Create(context.Background(), sa)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, sa.Id)
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		// act
+		err := st.Create(context.Background(), &Sale{Quantity: 2, ProductId: 2, InvoiceId: 1})
+
+		// assert
+		require.ErrorIs(t, err, ErrStorageSaleRelationProduct)
+		require.ErrorIs(t, err, ErrStorageSaleRelation)
+	})
+
+	t.Run("invoice not found", func(t *testing.T) {
+		// act
+		err := st.Update(context.Background(), &Sale{Id: 1, Quantity: 2, ProductId: 1, InvoiceId: 2})
+
+		// assert
+		require.ErrorIs(t, err, ErrStorageSaleRelationInvoice)
+	})
+}
+
+func TestStorageSaleMap_ReadPage(t *testing.T) {
+	// arrange
+	db := memdb.NewDB()
+	_ = db.Update(func(t *memdb.Tables) (err error) {
+		for id := 1; id <= 5; id++ {
+			t.Sales[id] = &memdb.SaleRow{Id: id, Quantity: id % 3, InvoiceId: 1 + id%2}
+		}
+		return
+	})
+	st := NewStorageSaleMap(db)
+
+	// act
+	ss, total, err := st.ReadPage(context.Background(), query.Query{
+		Filters: []query.Filter{{Field: "invoice_id", Value: 2}},
+		Sort:    []query.Sort{{Field: "quantity", Desc: true}},
+		Limit:   2,
+	})
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, []*Sale{{Id: 5, Quantity: 2, InvoiceId: 2}, {Id: 1, Quantity: 1, InvoiceId: 2}}, ss)
+}
@@ -1,8 +1,13 @@
 package storage
 
 import (
+	"app/internal/query"
+	"app/internal/sqltx"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/go-sql-driver/mysql"
 )
@@ -20,32 +25,68 @@ type SaleMySQL struct {
 	InvoiceId sql.NullInt32
 }
 
+// saleColumns are the columns (or expressions) of the SaleFields, also used by StorageSaleSQLite.
+// Null values are read as zero values, as in the serialization of the rows
+var saleColumns = map[string]string{
+	"id":         "id",
+	"quantity":   "COALESCE(quantity, 0)",
+	"product_id": "COALESCE(product_id, 0)",
+	"invoice_id": "COALESCE(invoice_id, 0)",
+}
+
 // StorageSaleMySQL is a struct that represents a sale storage in MySQL for StorageSale interface
 type StorageSaleMySQL struct {
-	db *sql.DB
+	// db is the database or the transaction the operations run on
+	db sqltx.Executor
+}
+
+// InTx returns a copy of the storage whose operations run in the transaction tx (see sqltx.Runner)
+func (s *StorageSaleMySQL) InTx(tx *sql.Tx) *StorageSaleMySQL {
+	return &StorageSaleMySQL{db: tx}
 }
 
 // ReadAll returns all sales
-func (s *StorageSaleMySQL) ReadAll() (ss []*Sale, err error) {
-	// query
-	query := "SELECT id, quantity, product_id, invoice_id FROM sales"
+func (s *StorageSaleMySQL) ReadAll(ctx context.Context) (ss []*Sale, err error) {
+	ss, err = s.read(ctx, "SELECT id, quantity, product_id, invoice_id FROM sales")
+	return
+}
+
+// ReadPage returns the page of sales of the query and the number of sales that match its filters
+func (s *StorageSaleMySQL) ReadPage(ctx context.Context, q query.Query) (ss []*Sale, total int, err error) {
+	where, whereArgs := q.Where(saleColumns)
+	page, pageArgs := q.Page(saleColumns)
 
+	// count
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sales"+where, whereArgs...).Scan(&total)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	// page
+	ss, err = s.read(ctx, "SELECT id, quantity, product_id, invoice_id FROM sales"+where+page, append(whereArgs, pageArgs...)...)
+	return
+}
+
+// read returns the sales selected by the query with the args
+func (s *StorageSaleMySQL) read(ctx context.Context, query string, args ...any) (ss []*Sale, err error) {
 	// prepare statement
 	var stmt *sql.Stmt
-	stmt, err = s.db.Prepare(query)
+	stmt, err = s.db.PrepareContext(ctx, query)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageSaleInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
 		return
 	}
 	defer stmt.Close()
 
 	// execute query
 	var rows *sql.Rows
-	rows, err = stmt.Query()
+	rows, err = stmt.QueryContext(ctx, args...)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageSaleInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
 		return
 	}
+	defer rows.Close()
 
 	// iterate rows
 	for rows.Next() {
@@ -53,7 +94,7 @@ func (s *StorageSaleMySQL) ReadAll() (ss []*Sale, err error) {
 		var saMySQL SaleMySQL
 		err = rows.Scan(&saMySQL.Id, &saMySQL.Quantity, &saMySQL.ProductId, &saMySQL.InvoiceId)
 		if err != nil {
-			err = fmt.Errorf("%w. %v", ErrStorageSaleInternal, err)
+			err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
 			return
 		}
 
@@ -66,13 +107,53 @@ func (s *StorageSaleMySQL) ReadAll() (ss []*Sale, err error) {
 
 		ss = append(ss, &sa)
 	}
-	rows.Close()
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	return
+}
+
+// ReadById returns the sale with the given id
+func (s *StorageSaleMySQL) ReadById(ctx context.Context, id int) (sa *Sale, err error) {
+	// query
+	query := "SELECT id, quantity, product_id, invoice_id FROM sales WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var saMySQL SaleMySQL
+	err = stmt.QueryRowContext(ctx, id).Scan(&saMySQL.Id, &saMySQL.Quantity, &saMySQL.ProductId, &saMySQL.InvoiceId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w. %v", ErrStorageSaleNotFound, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	// serialization
+	sa = new(Sale)
+	sa.Id = int(saMySQL.Id.Int32)
+	sa.Quantity = int(saMySQL.Quantity.Int32)
+	sa.ProductId = int(saMySQL.ProductId.Int32)
+	sa.InvoiceId = int(saMySQL.InvoiceId.Int32)
 
 	return
 }
 
 // Create inserts a new sale
-func (s *StorageSaleMySQL) Create(sa *Sale) (err error) {
+func (s *StorageSaleMySQL) Create(ctx context.Context, sa *Sale) (err error) {
 	// deserialization
 	var saMySQL SaleMySQL
 	if sa.Id != 0 {
@@ -93,33 +174,33 @@ func (s *StorageSaleMySQL) Create(sa *Sale) (err error) {
 	}
 
 	// query
-	query := "INSERT INTO sales (quantity, product_id, invoice_id) VALUES (?, ?, ?)"
+	query := "INSERT INTO sales (id, quantity, product_id, invoice_id) VALUES (?, ?, ?, ?)"
 
 	// prepare statement
 	var stmt *sql.Stmt
-	stmt, err = s.db.Prepare(query)
+	stmt, err = s.db.PrepareContext(ctx, query)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageSaleInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
 		return
 	}
 	defer stmt.Close()
 
 	// execute query
 	var result sql.Result
-	result, err = stmt.Exec(saMySQL.Quantity, saMySQL.ProductId, saMySQL.InvoiceId)
+	result, err = stmt.ExecContext(ctx, saMySQL.Id, saMySQL.Quantity, saMySQL.ProductId, saMySQL.InvoiceId)
 	if err != nil {
 		if mysqlErr, ok := err.(*mysql.MySQLError); ok {
 			switch mysqlErr.Number {
 			case 1452:
-				err = fmt.Errorf("%w. %v", ErrStorageSaleRelation, err)
+				err = fmt.Errorf("%w. %v", relationError(mysqlErr), err)
 			default:
-				err = fmt.Errorf("%w. %v", ErrStorageSaleInternal, err)
+				err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
 			}
 
 			return
 		}
 
-		err = fmt.Errorf("%w. %v", ErrStorageSaleInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
 		return
 	}
 
@@ -127,7 +208,7 @@ func (s *StorageSaleMySQL) Create(sa *Sale) (err error) {
 	var rowsAffected int64
 	rowsAffected, err = result.RowsAffected()
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageSaleInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
 		return		
 	}
 	if rowsAffected != 1 {
@@ -139,11 +220,124 @@ func (s *StorageSaleMySQL) Create(sa *Sale) (err error) {
 	var lastInsertId int64
 	lastInsertId, err = result.LastInsertId()
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrStorageSaleInternal, err)
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
 		return		
 	}
 
 	(*sa).Id = int(lastInsertId)
 
 	return
-}
\ No newline at end of file
+}
+
+// Update replaces the sale with the same id
+func (s *StorageSaleMySQL) Update(ctx context.Context, sa *Sale) (err error) {
+	// deserialization
+	var saMySQL SaleMySQL
+	if sa.Quantity != 0 {
+		saMySQL.Quantity.Valid = true
+		saMySQL.Quantity.Int32 = int32(sa.Quantity)
+	}
+	if sa.ProductId != 0 {
+		saMySQL.ProductId.Valid = true
+		saMySQL.ProductId.Int32 = int32(sa.ProductId)
+	}
+	if sa.InvoiceId != 0 {
+		saMySQL.InvoiceId.Valid = true
+		saMySQL.InvoiceId.Int32 = int32(sa.InvoiceId)
+	}
+
+	// query
+	query := "UPDATE sales SET quantity = ?, product_id = ?, invoice_id = ? WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, saMySQL.Quantity, saMySQL.ProductId, saMySQL.InvoiceId, sa.Id)
+	if err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+			switch mysqlErr.Number {
+			case 1452:
+				err = fmt.Errorf("%w. %v", relationError(mysqlErr), err)
+			default:
+				err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+			}
+
+			return
+		}
+
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	// check rows affected (0 when the sale does not exist or nothing changed)
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		_, err = s.ReadById(ctx, sa.Id)
+		return
+	}
+
+	return
+}
+
+// Delete removes the sale with the given id
+func (s *StorageSaleMySQL) Delete(ctx context.Context, id int) (err error) {
+	// query
+	query := "DELETE FROM sales WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		err = ErrStorageSaleNotFound
+		return
+	}
+
+	return
+}
+
+// relationError returns the relation error that matches the foreign key constraint of a MySQL 1452 error
+func relationError(mysqlErr *mysql.MySQLError) (err error) {
+	switch {
+	case strings.Contains(mysqlErr.Message, "fk_sales_product_id"):
+		err = ErrStorageSaleRelationProduct
+	case strings.Contains(mysqlErr.Message, "fk_sales_invoice_id"):
+		err = ErrStorageSaleRelationInvoice
+	default:
+		err = ErrStorageSaleRelation
+	}
+	return
+}
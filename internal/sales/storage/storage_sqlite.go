@@ -0,0 +1,332 @@
+package storage
+
+import (
+	"app/internal/query"
+	"app/internal/sqlitedb"
+	"app/internal/sqltx"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// NewStorageSaleSQLite returns a new instance of StorageSaleSQLite
+func NewStorageSaleSQLite(db *sql.DB) *StorageSaleSQLite {
+	return &StorageSaleSQLite{db: db}
+}
+
+// SaleSQLite is a struct that represents a sale in SQLite
+type SaleSQLite struct {
+	Id        sql.NullInt32
+	Quantity  sql.NullInt32
+	ProductId sql.NullInt32
+	InvoiceId sql.NullInt32
+}
+
+// StorageSaleSQLite is a struct that represents a sale storage in SQLite for StorageSale interface
+type StorageSaleSQLite struct {
+	// db is the database or the transaction the operations run on
+	db sqltx.Executor
+}
+
+// InTx returns a copy of the storage whose operations run in the transaction tx (see sqltx.Runner)
+func (s *StorageSaleSQLite) InTx(tx *sql.Tx) *StorageSaleSQLite {
+	return &StorageSaleSQLite{db: tx}
+}
+
+// ReadAll returns all sales
+func (s *StorageSaleSQLite) ReadAll(ctx context.Context) (ss []*Sale, err error) {
+	ss, err = s.read(ctx, "SELECT id, quantity, product_id, invoice_id FROM sales")
+	return
+}
+
+// ReadPage returns the page of sales of the query and the number of sales that match its filters
+func (s *StorageSaleSQLite) ReadPage(ctx context.Context, q query.Query) (ss []*Sale, total int, err error) {
+	where, whereArgs := q.Where(saleColumns)
+	page, pageArgs := q.Page(saleColumns)
+
+	// count
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sales"+where, whereArgs...).Scan(&total)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	// page
+	ss, err = s.read(ctx, "SELECT id, quantity, product_id, invoice_id FROM sales"+where+page, append(whereArgs, pageArgs...)...)
+	return
+}
+
+// read returns the sales selected by the query with the args
+func (s *StorageSaleSQLite) read(ctx context.Context, query string, args ...any) (ss []*Sale, err error) {
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var rows *sql.Rows
+	rows, err = stmt.QueryContext(ctx, args...)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	defer rows.Close()
+
+	// iterate rows
+	for rows.Next() {
+		// scan row
+		var saSQLite SaleSQLite
+		err = rows.Scan(&saSQLite.Id, &saSQLite.Quantity, &saSQLite.ProductId, &saSQLite.InvoiceId)
+		if err != nil {
+			err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+			return
+		}
+
+		// serialization
+		var sa Sale
+		sa.Id = int(saSQLite.Id.Int32)
+		sa.Quantity = int(saSQLite.Quantity.Int32)
+		sa.ProductId = int(saSQLite.ProductId.Int32)
+		sa.InvoiceId = int(saSQLite.InvoiceId.Int32)
+
+		ss = append(ss, &sa)
+	}
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	return
+}
+
+// ReadById returns the sale with the given id
+func (s *StorageSaleSQLite) ReadById(ctx context.Context, id int) (sa *Sale, err error) {
+	// query
+	query := "SELECT id, quantity, product_id, invoice_id FROM sales WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var saSQLite SaleSQLite
+	err = stmt.QueryRowContext(ctx, id).Scan(&saSQLite.Id, &saSQLite.Quantity, &saSQLite.ProductId, &saSQLite.InvoiceId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w. %v", ErrStorageSaleNotFound, err)
+			return
+		}
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	// serialization
+	sa = new(Sale)
+	sa.Id = int(saSQLite.Id.Int32)
+	sa.Quantity = int(saSQLite.Quantity.Int32)
+	sa.ProductId = int(saSQLite.ProductId.Int32)
+	sa.InvoiceId = int(saSQLite.InvoiceId.Int32)
+
+	return
+}
+
+// Create inserts a new sale
+func (s *StorageSaleSQLite) Create(ctx context.Context, sa *Sale) (err error) {
+	// deserialization
+	var saSQLite SaleSQLite
+	if sa.Id != 0 {
+		saSQLite.Id.Valid = true
+		saSQLite.Id.Int32 = int32(sa.Id)
+	}
+	if sa.Quantity != 0 {
+		saSQLite.Quantity.Valid = true
+		saSQLite.Quantity.Int32 = int32(sa.Quantity)
+	}
+	if sa.ProductId != 0 {
+		saSQLite.ProductId.Valid = true
+		saSQLite.ProductId.Int32 = int32(sa.ProductId)
+	}
+	if sa.InvoiceId != 0 {
+		saSQLite.InvoiceId.Valid = true
+		saSQLite.InvoiceId.Int32 = int32(sa.InvoiceId)
+	}
+
+	// query
+	query := "INSERT INTO sales (id, quantity, product_id, invoice_id) VALUES (?, ?, ?, ?)"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, saSQLite.Id, saSQLite.Quantity, saSQLite.ProductId, saSQLite.InvoiceId)
+	if err != nil {
+		if sqlitedb.IsForeignKeyError(err) {
+			err = fmt.Errorf("%w. %v", s.saleRelationError(ctx, saSQLite), err)
+			return
+		}
+
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return		
+	}
+	if rowsAffected != 1 {
+		err = fmt.Errorf("%w. %s", ErrStorageSaleInternal, "rows affected != 1")
+		return
+	}
+
+	// get last insert id
+	var lastInsertId int64
+	lastInsertId, err = result.LastInsertId()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return		
+	}
+
+	(*sa).Id = int(lastInsertId)
+
+	return
+}
+
+// Update replaces the sale with the same id
+func (s *StorageSaleSQLite) Update(ctx context.Context, sa *Sale) (err error) {
+	// deserialization
+	var saSQLite SaleSQLite
+	if sa.Quantity != 0 {
+		saSQLite.Quantity.Valid = true
+		saSQLite.Quantity.Int32 = int32(sa.Quantity)
+	}
+	if sa.ProductId != 0 {
+		saSQLite.ProductId.Valid = true
+		saSQLite.ProductId.Int32 = int32(sa.ProductId)
+	}
+	if sa.InvoiceId != 0 {
+		saSQLite.InvoiceId.Valid = true
+		saSQLite.InvoiceId.Int32 = int32(sa.InvoiceId)
+	}
+
+	// query
+	query := "UPDATE sales SET quantity = ?, product_id = ?, invoice_id = ? WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, saSQLite.Quantity, saSQLite.ProductId, saSQLite.InvoiceId, sa.Id)
+	if err != nil {
+		if sqlitedb.IsForeignKeyError(err) {
+			err = fmt.Errorf("%w. %v", s.saleRelationError(ctx, saSQLite), err)
+			return
+		}
+
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	// check rows affected (0 when the sale does not exist or nothing changed)
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		_, err = s.ReadById(ctx, sa.Id)
+		return
+	}
+
+	return
+}
+
+// Delete removes the sale with the given id
+func (s *StorageSaleSQLite) Delete(ctx context.Context, id int) (err error) {
+	// query
+	query := "DELETE FROM sales WHERE id = ?"
+
+	// prepare statement
+	var stmt *sql.Stmt
+	stmt, err = s.db.PrepareContext(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	defer stmt.Close()
+
+	// execute query
+	var result sql.Result
+	result, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+
+	// check rows affected
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("%w. %w", ErrStorageSaleInternal, err)
+		return
+	}
+	if rowsAffected == 0 {
+		err = ErrStorageSaleNotFound
+		return
+	}
+
+	return
+}
+
+// saleRelationError returns the relation error of a sale that violates a foreign key constraint
+// (SQLite does not report which constraint failed, so the related records are looked up)
+func (s *StorageSaleSQLite) saleRelationError(ctx context.Context, saSQLite SaleSQLite) (err error) {
+	exists := func(query string, id sql.NullInt32) bool {
+		if !id.Valid {
+			return true
+		}
+		var found bool
+		if err := s.db.QueryRowContext(ctx, query, id).Scan(&found); err != nil {
+			return true
+		}
+		return found
+	}
+
+	switch {
+	case !exists("SELECT EXISTS(SELECT 1 FROM products WHERE id = ?)", saSQLite.ProductId):
+		err = ErrStorageSaleRelationProduct
+	case !exists("SELECT EXISTS(SELECT 1 FROM invoices WHERE id = ?)", saSQLite.InvoiceId):
+		err = ErrStorageSaleRelationInvoice
+	default:
+		err = ErrStorageSaleRelation
+	}
+	return
+}
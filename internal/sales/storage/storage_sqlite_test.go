@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"app/internal/query"
+	"app/internal/sqlitedb"
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StorageSaleSQLite
+func TestStorageSaleSQLite_Create(t *testing.T) {
+	// arrange
+	db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("INSERT INTO products (id, price) VALUES (1, 10); INSERT INTO invoices (id) VALUES (1)")
+	require.NoError(t, err)
+	st := NewStorageSaleSQLite(db)
+
+	t.Run("valid relations", func(t *testing.T) {
+		// act
+		sa := &Sale{Quantity: 2, ProductId: 1, InvoiceId: 1}
+		err := st.Create(context.Background(), sa)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, sa.Id)
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		// act
+		err := st.Create(context.Background(), &Sale{Quantity: 2, ProductId: 2, InvoiceId: 1})
+
+		// assert
+		require.ErrorIs(t, err, ErrStorageSaleRelationProduct)
+	})
+
+	t.Run("invoice not found", func(t *testing.T) {
+		// act
+		err := st.Update(context.Background(), &Sale{Id: 1, Quantity: 2, ProductId: 1, InvoiceId: 2})
+
+		// assert
+		require.ErrorIs(t, err, ErrStorageSaleRelationInvoice)
+	})
+}
+
+func TestStorageSaleSQLite_ReadPage(t *testing.T) {
+	// arrange
+	db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("INSERT INTO invoices (id) VALUES (1), (2); " +
+		"INSERT INTO sales (id, quantity, invoice_id) VALUES (1, 1, 2), (2, 2, 1), (3, 0, 2), (4, 1, 1), (5, 2, 2)")
+	require.NoError(t, err)
+	st := NewStorageSaleSQLite(db)
+
+	q := query.Query{
+		Filters: []query.Filter{{Field: "invoice_id", Value: 2}},
+		Sort:    []query.Sort{{Field: "quantity", Desc: true}},
+		Limit:   2,
+	}
+
+	// act
+	ss, total, err := st.ReadPage(context.Background(), q)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, []*Sale{{Id: 5, Quantity: 2, InvoiceId: 2}, {Id: 1, Quantity: 1, InvoiceId: 2}}, ss)
+
+	t.Run("next cursor", func(t *testing.T) {
+		// arrange (a sale inserted before the cursor does not shift the next page)
+		_, err := db.Exec("INSERT INTO sales (id, quantity, invoice_id) VALUES (6, 3, 2)")
+		require.NoError(t, err)
+		next := query.Next(q, total, ss, SaleValue)
+		qNext, err := query.Parse(url.Values{"invoice_id": {"2"}, "sort": {"-quantity"}, "limit": {"2"}, "after": {next}}, SaleFields)
+		require.NoError(t, err)
+
+		// act
+		ss, total, err := st.ReadPage(context.Background(), qNext)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 4, total)
+		require.Equal(t, []*Sale{{Id: 3, Quantity: 0, InvoiceId: 2}}, ss)
+		require.Empty(t, query.Next(qNext, total, ss, SaleValue))
+	})
+}
+
+// Tests for StorageSaleSQLite.ReadAll method
+func TestStorageSaleSQLite_ReadAll(t *testing.T) {
+	t.Run("scan error releases the connection", func(t *testing.T) {
+		// arrange (a quantity that is not an integer can not be scanned; the memory database has a single connection)
+		db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = db.Exec("INSERT INTO products (id, price) VALUES (1, 10); INSERT INTO invoices (id) VALUES (1);" +
+			"INSERT INTO sales (quantity, product_id, invoice_id) VALUES (2, 1, 1), ('two', 1, 1)")
+		require.NoError(t, err)
+		st := NewStorageSaleSQLite(db)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		// act
+		ss, errRead := st.ReadAll(ctx)
+		sa, errNext := st.ReadById(ctx, 1)
+
+		// assert
+		require.ErrorIs(t, errRead, ErrStorageSaleInternal)
+		require.Len(t, ss, 1)
+		require.NoError(t, errNext)
+		require.Equal(t, 2, sa.Quantity)
+	})
+}
@@ -0,0 +1,53 @@
+-- DDL (SQLite equivalent of docs/db/mysql/database.sql)
+-- foreign keys are only enforced with PRAGMA foreign_keys = ON (set by sqlitedb.Open on every connection)
+
+-- Table: customers
+CREATE TABLE IF NOT EXISTS `customers` (
+    `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    `first_name` VARCHAR(45) NULL,
+    `last_name` VARCHAR(45) NULL,
+    `condition` BOOLEAN NULL
+);
+
+-- Table: invoices
+CREATE TABLE IF NOT EXISTS `invoices` (
+    `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    `datetime` DATETIME NULL,
+    `total` FLOAT NULL,
+    `customer_id` INTEGER NULL,
+    -- constraints
+    CONSTRAINT `fk_invoices_customer_id` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS `idx_invoices_customer_id` ON `invoices` (`customer_id`);
+
+-- Table: products
+CREATE TABLE IF NOT EXISTS `products` (
+    `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    `description` VARCHAR(100) NULL,
+    `price` FLOAT NULL
+);
+
+-- Table: sales
+CREATE TABLE IF NOT EXISTS `sales` (
+    `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    `quantity` INTEGER NULL,
+    `invoice_id` INTEGER NULL,
+    `product_id` INTEGER NULL,
+    -- constraints
+    CONSTRAINT `fk_sales_invoice_id` FOREIGN KEY (`invoice_id`) REFERENCES `invoices` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+    CONSTRAINT `fk_sales_product_id` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS `idx_sales_invoice_id` ON `sales` (`invoice_id`);
+CREATE INDEX IF NOT EXISTS `idx_sales_product_id` ON `sales` (`product_id`);
+
+-- Table: api_keys
+CREATE TABLE IF NOT EXISTS `api_keys` (
+    `id` INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    `name` VARCHAR(100) NOT NULL,
+    `key_hash` CHAR(64) NOT NULL,
+    `role` VARCHAR(20) NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `revoked_at` DATETIME NULL,
+    -- constraints
+    CONSTRAINT `uq_api_keys_key_hash` UNIQUE (`key_hash`)
+);
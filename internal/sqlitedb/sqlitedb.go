@@ -0,0 +1,69 @@
+package sqlitedb
+
+import (
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Schema is the DDL of the database, equivalent to docs/db/mysql/database.sql
+//
+//go:embed schema.sql
+var Schema string
+
+// MemoryPath is the path of a private in-memory database
+const MemoryPath = ":memory:"
+
+// Open opens the SQLite database at path (MemoryPath for an in-memory one) with foreign keys enforced
+// on every connection and creates the tables that do not exist
+func Open(path string) (db *sql.DB, err error) {
+	// dsn
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Add("_time_format", "sqlite")
+	dsn := "file:" + path + "?" + q.Encode()
+
+	db, err = sql.Open("sqlite", dsn)
+	if err != nil {
+		return
+	}
+	if path == MemoryPath {
+		// each connection to :memory: is a different database
+		db.SetMaxOpenConns(1)
+	}
+
+	// schema
+	_, err = db.Exec(Schema)
+	if err != nil {
+		db.Close()
+		db = nil
+		err = fmt.Errorf("sqlitedb: schema: %w", err)
+		return
+	}
+
+	return
+}
+
+// IsForeignKeyError reports whether err is a foreign key constraint violation
+// (the SQLite equivalent of MySQL errors 1451 and 1452). ON DELETE RESTRICT actions are
+// reported with the trigger extended code, so both codes are checked
+func IsForeignKeyError(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return true
+	case sqlite3.SQLITE_CONSTRAINT_TRIGGER:
+		return strings.Contains(sqliteErr.Error(), "FOREIGN KEY")
+	}
+	return false
+}
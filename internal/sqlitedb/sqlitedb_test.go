@@ -0,0 +1,37 @@
+package sqlitedb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Open function
+func TestOpen(t *testing.T) {
+	t.Run("foreign keys are enforced", func(t *testing.T) {
+		// arrange
+		db, err := Open(MemoryPath)
+		require.NoError(t, err)
+		defer db.Close()
+
+		// act
+		_, err = db.Exec("INSERT INTO invoices (customer_id) VALUES (1)")
+
+		// assert
+		require.Error(t, err)
+		require.True(t, IsForeignKeyError(err))
+	})
+
+	t.Run("schema is idempotent", func(t *testing.T) {
+		// arrange
+		db, err := Open(MemoryPath)
+		require.NoError(t, err)
+		defer db.Close()
+
+		// act
+		_, err = db.Exec(Schema)
+
+		// assert
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,68 @@
+package sqltx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Executor is an interface that represents the methods shared by *sql.DB and *sql.Tx that the storages use,
+// so the same storage code runs on its own or as part of a transaction
+type Executor interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+var (
+	// ErrTx is returned when a transaction can not be started, committed or rolled back
+	ErrTx = errors.New("transaction error")
+)
+
+// NewRunner returns a new instance of Runner
+func NewRunner(db *sql.DB) *Runner {
+	return &Runner{db: db}
+}
+
+// Runner is a struct that represents a unit of work runner: it runs functions in transactions of a database
+type Runner struct {
+	db *sql.DB
+}
+
+// WithTx runs fn in a new transaction, that is committed if fn returns nil and rolled back otherwise
+// (also when fn panics, the panic is propagated after the rollback). The error of fn is returned as is
+func (r *Runner) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	// begin
+	var tx *sql.Tx
+	tx, err = r.db.BeginTx(ctx, nil)
+	if err != nil {
+		err = fmt.Errorf("%w. begin: %w", ErrTx, err)
+		return
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	// run
+	err = fn(tx)
+	if err != nil {
+		if errRollback := tx.Rollback(); errRollback != nil {
+			err = fmt.Errorf("%w (%w. rollback: %v)", err, ErrTx, errRollback)
+		}
+		return
+	}
+
+	// commit
+	err = tx.Commit()
+	if err != nil {
+		err = fmt.Errorf("%w. commit: %w", ErrTx, err)
+		return
+	}
+
+	return
+}
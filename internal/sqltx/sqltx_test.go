@@ -0,0 +1,93 @@
+package sqltx
+
+import (
+	"app/internal/sqlitedb"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Runner.WithTx method
+func TestRunner_WithTx(t *testing.T) {
+	// arrange
+	db, err := sqlitedb.Open(sqlitedb.MemoryPath)
+	require.NoError(t, err)
+	defer db.Close()
+	runner := NewRunner(db)
+	insert := func(tx *sql.Tx) error {
+		_, err := tx.Exec("INSERT INTO customers (first_name) VALUES ('Ike')")
+		return err
+	}
+	count := func() (n int) {
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM customers").Scan(&n))
+		return
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		// act
+		err := runner.WithTx(context.Background(), insert)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, count())
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		// arrange
+		errFn := errors.New("fn error")
+
+		// act
+		err := runner.WithTx(context.Background(), func(tx *sql.Tx) error {
+			require.NoError(t, insert(tx))
+			return errFn
+		})
+
+		// assert
+		require.ErrorIs(t, err, errFn)
+		require.Equal(t, 1, count())
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		// act
+		fn := func() {
+			_ = runner.WithTx(context.Background(), func(tx *sql.Tx) error {
+				require.NoError(t, insert(tx))
+				panic("fn panic")
+			})
+		}
+
+		// assert
+		require.PanicsWithValue(t, "fn panic", fn)
+		require.Equal(t, 1, count())
+	})
+
+	t.Run("begin error", func(t *testing.T) {
+		// arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// act
+		err := runner.WithTx(ctx, insert)
+
+		// assert
+		require.ErrorIs(t, err, ErrTx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("begin after the deadline keeps the cause", func(t *testing.T) {
+		// arrange
+		ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+
+		// act
+		err := runner.WithTx(ctx, insert)
+
+		// assert
+		require.ErrorIs(t, err, ErrTx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"app/pkg/web/response"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Logger returns a middleware that logs an access record of each request to l, with its method, path,
+// status, latency and response size. Server errors (5xx) are logged as errors, the rest as info
+func Logger(l *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := response.NewStatusWriter(w)
+			defer func() {
+				level := slog.LevelInfo
+				if sw.Status() >= http.StatusInternalServerError {
+					level = slog.LevelError
+				}
+				l.LogAttrs(r.Context(), level, "request",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.Int("status", sw.Status()),
+					slog.Duration("latency", time.Since(start)),
+					slog.Int("size", sw.Size()),
+				)
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
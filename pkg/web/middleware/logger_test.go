@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Logger function
+func TestLogger(t *testing.T) {
+	type input struct { status int; body string }
+	type output struct { level string; status float64; size float64 }
+	type testCase struct {
+		name string
+		input input
+		output output
+	}
+
+	cases := []testCase{
+		{
+			name: "success is info",
+			input: input{status: http.StatusOK, body: `{"message":"Success"}`},
+			output: output{level: "INFO", status: 200, size: 21},
+		},
+		{
+			name: "client error is info",
+			input: input{status: http.StatusNotFound, body: ""},
+			output: output{level: "INFO", status: 404, size: 0},
+		},
+		{
+			name: "server error is error",
+			input: input{status: http.StatusInternalServerError, body: "oops"},
+			output: output{level: "ERROR", status: 500, size: 4},
+		},
+	}
+
+	// run tests
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			var buf bytes.Buffer
+			l := slog.New(slog.NewJSONHandler(&buf, nil))
+			hd := Logger(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.input.status)
+				w.Write([]byte(c.input.body))
+			}))
+
+			// act
+			hd.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/products?limit=1", nil))
+
+			// assert
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+			require.Equal(t, c.output.level, record["level"])
+			require.Equal(t, "request", record["msg"])
+			require.Equal(t, "POST", record["method"])
+			require.Equal(t, "/products", record["path"])
+			require.Equal(t, c.output.status, record["status"])
+			require.Equal(t, c.output.size, record["size"])
+			require.Contains(t, record, "latency")
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import "net/http"
+
+// Middleware is a function that wraps a handler with some behavior
+type Middleware func(next http.Handler) http.Handler
+
+// Chain returns a middleware that applies mws in order, the first one being the outermost
+// (Chain(a, b)(h) is a(b(h)))
+func Chain(mws ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"app/pkg/web/response"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// errorBody is the standard response envelope of the errors written by the middlewares
+type errorBody struct {
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+	Error   bool   `json:"error"`
+}
+
+// Recovery returns a middleware that recovers the panics of the handlers, logs them to l with their stack
+// and responds 500 with the standard error envelope. If the handler already started the response,
+// the connection is aborted instead, so the client does not take a truncated response as complete
+func Recovery(l *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw := response.NewStatusWriter(w)
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				l.LogAttrs(r.Context(), slog.LevelError, "panic recovered",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.Any("panic", rec),
+					slog.String("stack", string(debug.Stack())),
+				)
+				if sw.Written() {
+					panic(http.ErrAbortHandler)
+				}
+				response.JSON(sw, http.StatusInternalServerError, &errorBody{Message: "Internal server error", Data: nil, Error: true})
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Recovery function
+func TestRecovery(t *testing.T) {
+	t.Run("panic before the response is 500 with the error envelope", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		hd := Recovery(slog.New(slog.NewJSONHandler(&buf, nil)))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+		res := httptest.NewRecorder()
+
+		// act
+		hd.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/sales", nil))
+
+		// assert
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		require.JSONEq(t, `{"message":"Internal server error","data":null,"error":true}`, res.Body.String())
+		require.Contains(t, buf.String(), `"msg":"panic recovered"`)
+		require.Contains(t, buf.String(), `"panic":"boom"`)
+		require.Contains(t, buf.String(), `"stack":`)
+	})
+
+	t.Run("panic after the response aborts the connection", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		hd := Recovery(slog.New(slog.NewJSONHandler(&buf, nil)))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			panic("boom")
+		}))
+
+		// act & assert
+		require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			hd.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/sales", nil))
+		})
+		require.Contains(t, buf.String(), `"panic":"boom"`)
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		hd := Recovery(slog.New(slog.NewJSONHandler(&buf, nil)))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		res := httptest.NewRecorder()
+
+		// act
+		hd.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/sales", nil))
+
+		// assert
+		require.Equal(t, http.StatusNoContent, res.Code)
+		require.Empty(t, buf.String())
+	})
+}
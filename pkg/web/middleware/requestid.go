@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+)
+
+// HeaderRequestID is the header of the id of a request, read from the request and echoed in the response
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength is the maximum length of an incoming request id (longer ones are replaced)
+const maxRequestIDLength = 128
+
+// requestIDKey is the context key of the request id
+type requestIDKey struct{}
+
+// RequestID returns a middleware that sets the id of each request: the X-Request-ID header of the request
+// if it is valid, or a new random one. The id is stored in the request context and echoed in the response header
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(HeaderRequestID)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(HeaderRequestID, id)
+
+			next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), id)))
+		})
+	}
+}
+
+// ContextWithRequestID returns a copy of ctx with the request id
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the request id of ctx, or an empty string if it has none
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestIDHandler returns a slog handler that adds the request id of the context of each record
+// (as request_id) and passes it to h. Records logged with a context without id are passed as they are
+func NewRequestIDHandler(h slog.Handler) slog.Handler {
+	return &requestIDHandler{Handler: h}
+}
+
+// requestIDHandler is a slog handler that adds the request id of the context to the records
+type requestIDHandler struct {
+	slog.Handler
+}
+
+// Handle adds the request id of ctx to the record and handles it
+func (h *requestIDHandler) Handle(ctx context.Context, rec slog.Record) error {
+	if id := RequestIDFrom(ctx); id != "" {
+		rec.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, rec)
+}
+
+// WithAttrs returns a handler with the attributes that keeps adding the request id
+func (h *requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &requestIDHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler with the group that keeps adding the request id
+func (h *requestIDHandler) WithGroup(name string) slog.Handler {
+	return &requestIDHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// validRequestID reports whether id is a non-empty and not too long token of letters, digits, '-', '_', '.' and ':'
+// (so it can not inject anything in the logs)
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random id of 32 hex characters
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
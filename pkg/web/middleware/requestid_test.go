@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for RequestID function
+func TestRequestID(t *testing.T) {
+	type input struct { header string }
+	type output struct { keep bool }
+	type testCase struct {
+		name string
+		input input
+		output output
+	}
+
+	cases := []testCase{
+		{
+			name: "valid header is kept",
+			input: input{header: "abc-123_x.y:z"},
+			output: output{keep: true},
+		},
+		{
+			name: "missing header is generated",
+			input: input{header: ""},
+			output: output{keep: false},
+		},
+		{
+			name: "header with invalid characters is replaced",
+			input: input{header: "abc\" injected=1"},
+			output: output{keep: false},
+		},
+		{
+			name: "too long header is replaced",
+			input: input{header: strings.Repeat("a", 129)},
+			output: output{keep: false},
+		},
+	}
+
+	// run tests
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			var id string
+			hd := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				id = RequestIDFrom(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.input.header != "" {
+				req.Header.Set(HeaderRequestID, c.input.header)
+			}
+			res := httptest.NewRecorder()
+
+			// act
+			hd.ServeHTTP(res, req)
+
+			// assert
+			require.Equal(t, id, res.Header().Get(HeaderRequestID))
+			if c.output.keep {
+				require.Equal(t, c.input.header, id)
+			} else {
+				require.Regexp(t, "^[0-9a-f]{32}$", id)
+			}
+		})
+	}
+}
+
+// Tests for NewRequestIDHandler function
+func TestNewRequestIDHandler(t *testing.T) {
+	t.Run("adds the request id of the context", func(t *testing.T) {
+		// arrange
+		var buf bytes.Buffer
+		l := slog.New(NewRequestIDHandler(slog.NewJSONHandler(&buf, nil))).With(slog.String("app", "server"))
+		ctx := ContextWithRequestID(context.Background(), "req-1")
+
+		// act
+		l.InfoContext(ctx, "with id")
+		l.Info("without id")
+
+		// assert
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		require.Contains(t, lines[0], `"app":"server"`)
+		require.Contains(t, lines[0], `"request_id":"req-1"`)
+		require.NotContains(t, lines[1], "request_id")
+	})
+}
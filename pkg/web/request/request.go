@@ -4,23 +4,106 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"regexp"
 	"strings"
 )
 
-// JSON decodes json from request body to ptr
+// Option is a check of the json decoded by JSON
+type Option func(c *config)
+
+// config is a struct that represents the checks of JSON
+type config struct {
+	disallowUnknownFields bool
+	singleObject          bool
+	maxBytes              int64
+	contentType           bool
+}
+
+// DisallowUnknownFields rejects bodies with fields that are not in the destination struct
+func DisallowUnknownFields() Option {
+	return func(c *config) { c.disallowUnknownFields = true }
+}
+
+// SingleObject rejects bodies with anything after the json value (other than whitespace)
+func SingleObject() Option {
+	return func(c *config) { c.singleObject = true }
+}
+
+// MaxBytes rejects bodies larger than n bytes (the server is told to close the connection, instead of reading the rest)
+func MaxBytes(n int64) Option {
+	return func(c *config) { c.maxBytes = n }
+}
+
+// ContentType rejects requests whose Content-Type is not application/json
+func ContentType() Option {
+	return func(c *config) { c.contentType = true }
+}
+
+// JSON decodes json from request body to ptr, with the checks of the options (w is the writer of the response to r)
 var (
 	ErrRequestJSONInvalid = errors.New("request json invalid")
+	// ErrRequestJSONUnknownField is returned when the body has a field that is not in ptr (DisallowUnknownFields)
+	ErrRequestJSONUnknownField = fmt.Errorf("%w: unknown field", ErrRequestJSONInvalid)
+	// ErrRequestJSONTrailingData is returned when the body has data after the json value (SingleObject)
+	ErrRequestJSONTrailingData = fmt.Errorf("%w: trailing data", ErrRequestJSONInvalid)
+	// ErrRequestJSONTooLarge is returned when the body is larger than the limit (MaxBytes)
+	ErrRequestJSONTooLarge = fmt.Errorf("%w: body too large", ErrRequestJSONInvalid)
+	// ErrRequestJSONContentType is returned when the Content-Type is not application/json (ContentType)
+	ErrRequestJSONContentType = fmt.Errorf("%w: content type not json", ErrRequestJSONInvalid)
 )
-func JSON(r *http.Request, ptr any) (err error) {
+func JSON(w http.ResponseWriter, r *http.Request, ptr any, opts ...Option) (err error) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	// check content type
+	if c.contentType {
+		mediaType, _, e := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if e != nil || mediaType != "application/json" {
+			err = fmt.Errorf("%w. %q", ErrRequestJSONContentType, r.Header.Get("Content-Type"))
+			return
+		}
+	}
+
 	// get body
-	err = json.NewDecoder(r.Body).Decode(ptr)
+	body := r.Body
+	if c.maxBytes > 0 {
+		body = http.MaxBytesReader(w, body, c.maxBytes)
+	}
+	dec := json.NewDecoder(body)
+	if c.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	err = dec.Decode(ptr)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrRequestJSONInvalid, err)
+		var errMaxBytes *http.MaxBytesError
+		switch {
+		case errors.As(err, &errMaxBytes):
+			err = fmt.Errorf("%w. limit of %d bytes", ErrRequestJSONTooLarge, errMaxBytes.Limit)
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			err = fmt.Errorf("%w %s", ErrRequestJSONUnknownField, strings.TrimPrefix(err.Error(), "json: unknown field "))
+		default:
+			err = fmt.Errorf("%w. %v", ErrRequestJSONInvalid, err)
+		}
 		return
 	}
 
+	// check nothing follows the json value
+	if c.singleObject {
+		var errMaxBytes *http.MaxBytesError
+		switch _, e := dec.Token(); {
+		case e == io.EOF:
+		case errors.As(e, &errMaxBytes):
+			err = fmt.Errorf("%w. limit of %d bytes", ErrRequestJSONTooLarge, errMaxBytes.Limit)
+		default:
+			err = fmt.Errorf("%w after offset %d", ErrRequestJSONTrailingData, dec.InputOffset())
+		}
+	}
+
 	return
 }
 
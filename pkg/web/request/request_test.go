@@ -2,7 +2,9 @@ package request
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -66,4 +68,112 @@ func TestPathParam(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+// Tests for JSON function
+func TestJSON(t *testing.T) {
+	type body struct { Name string `json:"name"` }
+	strict := []Option{ContentType(), MaxBytes(32), DisallowUnknownFields(), SingleObject()}
+	newRequest := func(contentType, b string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(b))
+		if contentType != "" {
+			r.Header.Set("Content-Type", contentType)
+		}
+		return r
+	}
+
+	type input struct { r *http.Request; opts []Option }
+	type output struct { value body; err error }
+	type testCase struct {
+		name string
+		input input
+		output output
+	}
+
+	cases := []testCase{
+		// valid cases
+		{
+			name: "no options accepts unknown fields and trailing data",
+			input: input{r: newRequest("", `{"name":"a","price":1} garbage`), opts: nil},
+			output: output{value: body{Name: "a"}, err: nil},
+		},
+		{
+			name: "strict, json with charset",
+			input: input{r: newRequest("application/json; charset=utf-8", " {\"name\":\"a\"}\n"), opts: strict},
+			output: output{value: body{Name: "a"}, err: nil},
+		},
+
+		// invalid cases
+		{
+			name: "malformed json",
+			input: input{r: newRequest("application/json", `{"name":`), opts: strict},
+			output: output{value: body{}, err: ErrRequestJSONInvalid},
+		},
+		{
+			name: "unknown field",
+			input: input{r: newRequest("application/json", `{"name":"a","price":1}`), opts: strict},
+			output: output{value: body{Name: "a"}, err: ErrRequestJSONUnknownField},
+		},
+		{
+			name: "trailing data",
+			input: input{r: newRequest("application/json", `{"name":"a"}{"name":"b"}`), opts: strict},
+			output: output{value: body{Name: "a"}, err: ErrRequestJSONTrailingData},
+		},
+		{
+			name: "body too large",
+			input: input{r: newRequest("application/json", `{"name":"`+strings.Repeat("a", 32)+`"}`), opts: strict},
+			output: output{value: body{}, err: ErrRequestJSONTooLarge},
+		},
+		{
+			name: "missing content type",
+			input: input{r: newRequest("", `{"name":"a"}`), opts: strict},
+			output: output{value: body{}, err: ErrRequestJSONContentType},
+		},
+		{
+			name: "content type not json",
+			input: input{r: newRequest("text/plain", `{"name":"a"}`), opts: strict},
+			output: output{value: body{}, err: ErrRequestJSONContentType},
+		},
+	}
+
+	// run tests
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			var value body
+
+			// act
+			err := JSON(httptest.NewRecorder(), c.input.r, &value, c.input.opts...)
+
+			// assert
+			require.Equal(t, c.output.value, value)
+			require.ErrorIs(t, err, c.output.err)
+			if c.output.err != nil {
+				require.ErrorIs(t, err, ErrRequestJSONInvalid)
+			}
+		})
+	}
+}
+
+// Tests for the MaxBytes option of JSON function in a server
+func TestJSON_MaxBytesServer(t *testing.T) {
+	t.Run("too large body closes the connection", func(t *testing.T) {
+		// arrange
+		var errJSON error
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var value map[string]any
+			errJSON = JSON(w, r, &value, MaxBytes(16))
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}))
+		defer srv.Close()
+
+		// act
+		res, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"name":"`+strings.Repeat("a", 1<<16)+`"}`))
+
+		// assert
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.ErrorIs(t, errJSON, ErrRequestJSONTooLarge)
+		require.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+		require.True(t, res.Close)
+	})
+}
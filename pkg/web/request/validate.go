@@ -0,0 +1,167 @@
+package request
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError is a validation problem of a field of a request body
+type FieldError struct {
+	// Field is the json name of the field (nested fields as items[0].quantity)
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Validate checks the fields of the struct pointed by ptr against the rules of their validate tags
+// and returns a FieldError for every invalid field (nil if all are valid). The rules are comma separated:
+//   - required: the value is not zero (a string is not blank, a slice is not empty)
+//   - min=n, max=n: a number is in the range, or a string (characters) or slice (items) length is
+//   - gt=n: a number is greater than n
+//
+// Nested structs and slices of structs are validated too. An unknown rule panics (it is a programming error)
+func Validate(ptr any) (fields []FieldError) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	validateStruct(v, "", &fields)
+	return
+}
+
+// validateStruct validates the fields of the struct v, whose path is prefix
+func validateStruct(v reflect.Value, prefix string, fields *[]FieldError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := jsonName(sf)
+		if name == "-" {
+			continue
+		}
+		path := prefix + name
+		fv := v.Field(i)
+
+		// rules
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			if msg := validateRules(fv, tag); msg != "" {
+				*fields = append(*fields, FieldError{Field: path, Message: msg})
+				continue
+			}
+		}
+
+		// nested
+		validateNested(fv, path, fields)
+	}
+}
+
+// validateNested validates the structs in v (a struct, a pointer to a struct or a slice of them)
+func validateNested(v reflect.Value, path string, fields *[]FieldError) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			validateNested(v.Elem(), path, fields)
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			return
+		}
+		validateStruct(v, path+".", fields)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i)
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if item.Kind() == reflect.Pointer && item.IsNil() {
+				*fields = append(*fields, FieldError{Field: itemPath, Message: "is required"})
+				continue
+			}
+			validateNested(item, itemPath, fields)
+		}
+	}
+}
+
+// validateRules returns the message of the first rule of tag that v breaks, or an empty string
+func validateRules(v reflect.Value, tag string) (msg string) {
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if isBlank(v) {
+				return "is required"
+			}
+		case "min", "max", "gt":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				panic(fmt.Sprintf("request: invalid validate rule %q", rule))
+			}
+			if msg = validateBound(v, name, n, param); msg != "" {
+				return
+			}
+		default:
+			panic(fmt.Sprintf("request: unknown validate rule %q", rule))
+		}
+	}
+	return
+}
+
+// validateBound returns the message of a min, max or gt rule that v breaks, or an empty string
+func validateBound(v reflect.Value, rule string, n float64, param string) (msg string) {
+	// value and unit of the message
+	var value float64
+	var unit string
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		value = v.Float()
+	case reflect.String:
+		value, unit = float64(utf8.RuneCountInString(v.String())), " characters"
+	case reflect.Slice:
+		value, unit = float64(v.Len()), " items"
+	default:
+		panic(fmt.Sprintf("request: validate rule %q on a %s", rule, v.Kind()))
+	}
+
+	switch {
+	case rule == "min" && value < n:
+		if unit != "" {
+			return "must have at least " + param + unit
+		}
+		return "must be at least " + param
+	case rule == "max" && value > n:
+		if unit != "" {
+			return "must have at most " + param + unit
+		}
+		return "must be at most " + param
+	case rule == "gt" && value <= n:
+		return "must be greater than " + param
+	}
+	return
+}
+
+// isBlank reports whether v is zero, a blank string or an empty slice
+func isBlank(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// jsonName returns the name of the field in json
+func jsonName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" {
+		name = sf.Name
+	}
+	return name
+}
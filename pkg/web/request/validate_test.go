@@ -0,0 +1,99 @@
+package request
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Validate function
+func TestValidate(t *testing.T) {
+	type item struct {
+		Quantity int `json:"quantity" validate:"gt=0"`
+	}
+	type body struct {
+		Name     string    `json:"name" validate:"required,max=5"`
+		Price    float64   `json:"price" validate:"min=0"`
+		Datetime time.Time `json:"datetime" validate:"required"`
+		Items    []*item   `json:"items" validate:"required"`
+		Note     string    `json:"note"`
+	}
+	valid := func() body {
+		return body{Name: "Beans", Price: 0, Datetime: time.Date(2022, 5, 15, 0, 0, 0, 0, time.UTC), Items: []*item{{Quantity: 1}}}
+	}
+
+	type input struct { b func() body }
+	type output struct { fields []FieldError }
+	type testCase struct {
+		name string
+		input input
+		output output
+	}
+
+	cases := []testCase{
+		// valid cases
+		{
+			name: "valid body",
+			input: input{b: valid},
+			output: output{fields: nil},
+		},
+
+		// invalid cases
+		{
+			name: "blank required string",
+			input: input{b: func() body { b := valid(); b.Name = "  "; return b }},
+			output: output{fields: []FieldError{{Field: "name", Message: "is required"}}},
+		},
+		{
+			name: "string longer than max",
+			input: input{b: func() body { b := valid(); b.Name = "Banana"; return b }},
+			output: output{fields: []FieldError{{Field: "name", Message: "must have at most 5 characters"}}},
+		},
+		{
+			name: "number less than min",
+			input: input{b: func() body { b := valid(); b.Price = -0.5; return b }},
+			output: output{fields: []FieldError{{Field: "price", Message: "must be at least 0"}}},
+		},
+		{
+			name: "zero required time and empty required slice",
+			input: input{b: func() body { b := valid(); b.Datetime = time.Time{}; b.Items = nil; return b }},
+			output: output{fields: []FieldError{
+				{Field: "datetime", Message: "is required"},
+				{Field: "items", Message: "is required"},
+			}},
+		},
+		{
+			name: "invalid and missing nested items",
+			input: input{b: func() body { b := valid(); b.Items = []*item{{Quantity: 1}, {Quantity: -5}, nil}; return b }},
+			output: output{fields: []FieldError{
+				{Field: "items[1].quantity", Message: "must be greater than 0"},
+				{Field: "items[2]", Message: "is required"},
+			}},
+		},
+	}
+
+	// run tests
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			b := c.input.b()
+
+			// act
+			fields := Validate(&b)
+
+			// assert
+			require.Equal(t, c.output.fields, fields)
+		})
+	}
+
+	t.Run("unknown rule panics", func(t *testing.T) {
+		// arrange
+		b := struct {
+			Name string `json:"name" validate:"email"`
+		}{}
+
+		// act & assert
+		require.Panics(t, func() { Validate(&b) })
+	})
+}
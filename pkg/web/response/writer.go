@@ -0,0 +1,58 @@
+package response
+
+import "net/http"
+
+// NewStatusWriter is a constructor for a StatusWriter that wraps w
+func NewStatusWriter(w http.ResponseWriter) *StatusWriter {
+	return &StatusWriter{ResponseWriter: w}
+}
+
+// StatusWriter is a http.ResponseWriter that records the status code and the size of the response it writes
+type StatusWriter struct {
+	http.ResponseWriter
+	// status is the status code written (0 until the header is written)
+	status int
+	// size is the number of bytes of the body written
+	size int
+}
+
+// WriteHeader records the status code (only the first one is sent) and writes it
+func (w *StatusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write records the size of b (and the implicit 200 status if the header was not written) and writes it
+func (w *StatusWriter) Write(b []byte) (n int, err error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err = w.ResponseWriter.Write(b)
+	w.size += n
+	return
+}
+
+// Status returns the status code of the response (200 if nothing was written, as net/http sends)
+func (w *StatusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Size returns the number of bytes of the body written
+func (w *StatusWriter) Size() int {
+	return w.size
+}
+
+// Written reports whether the header was written (so the status can not be changed)
+func (w *StatusWriter) Written() bool {
+	return w.status != 0
+}
+
+// Unwrap returns the wrapped writer (used by http.ResponseController)
+func (w *StatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,52 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for StatusWriter struct
+func TestStatusWriter(t *testing.T) {
+	t.Run("records the status and size", func(t *testing.T) {
+		// arrange
+		rec := httptest.NewRecorder()
+		w := NewStatusWriter(rec)
+
+		// act
+		JSON(w, http.StatusNotFound, map[string]string{"message": "not found"})
+
+		// assert
+		require.True(t, w.Written())
+		require.Equal(t, http.StatusNotFound, w.Status())
+		require.Equal(t, rec.Body.Len(), w.Size())
+	})
+
+	t.Run("keeps the first status", func(t *testing.T) {
+		// arrange
+		w := NewStatusWriter(httptest.NewRecorder())
+
+		// act
+		w.Write([]byte("ok"))
+		w.WriteHeader(http.StatusInternalServerError)
+
+		// assert
+		require.Equal(t, http.StatusOK, w.Status())
+		require.Equal(t, 2, w.Size())
+	})
+
+	t.Run("nothing written", func(t *testing.T) {
+		// arrange
+		w := NewStatusWriter(httptest.NewRecorder())
+
+		// act
+		// ...
+
+		// assert
+		require.False(t, w.Written())
+		require.Equal(t, http.StatusOK, w.Status())
+		require.Equal(t, 0, w.Size())
+	})
+}